@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/compiler"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// A config is a project config file (given by the -config flag), which lists the Go files to
+// generate in a single invocation. For example:
+//
+//	{
+//	  "options": {"EmitValidateMethods": true},
+//	  "targets": [
+//	    {
+//	      "schemas": ["schemas/repo.schema.json"],
+//	      "output": "repo/schema.go",
+//	      "package": "repo",
+//	      "importPath": "example.com/project/repo"
+//	    },
+//	    {
+//	      "schemas": ["schemas/site.schema.json"],
+//	      "output": "site/schema.go",
+//	      "package": "site",
+//	      "typeNames": {"schemas/site.schema.json#/definitions/auth": "AuthConfig"},
+//	      "goTypes": {"schemas/site.schema.json#/definitions/timeout": "time.Duration"}
+//	    }
+//	  ]
+//	}
+//
+// Paths are relative to the directory that contains the config file. The JSON Schema files of all
+// targets (and the documents they refer to) are loaded once, so that $refs resolve to the same
+// schemas in all targets. If a target has an import path, the other targets import its Go package
+// to refer to the Go types for its JSON Schema files (instead of emitting their own).
+type config struct {
+	// Options are the compiler options (see compiler.Options) for all targets. They override the
+	// options given by flags. The targets' type names and Go types are added to those in Options.
+	Options json.RawMessage `json:"options"`
+
+	Targets []*configTarget `json:"targets"`
+}
+
+// configTarget is a Go file to generate, as listed in a config.
+type configTarget struct {
+	Schemas    []string `json:"schemas"`    // the JSON Schema files
+	Output     string   `json:"output"`     // the Go file
+	Package    string   `json:"package"`    // the Go package name (default: the -pkg flag)
+	ImportPath string   `json:"importPath"` // the import path of the Go package (optional)
+
+	// TypeNames overrides the names of Go types (see compiler.Options.TypeNames). The key is a JSON
+	// Schema file path with a JSON Pointer fragment, such as "a.json#/definitions/foo".
+	TypeNames map[string]string `json:"typeNames"`
+
+	// GoTypes binds schemas to existing Go types (see compiler.Options.GoTypes). The key is as for
+	// TypeNames.
+	GoTypes map[string]string `json:"goTypes"`
+
+	roots []*jsonschema.Schema // the schemas read from the JSON Schema files
+}
+
+// readConfig reads and checks the config file at path.
+func readConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var c config
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+
+	if len(c.Targets) == 0 {
+		return nil, errors.New("no targets listed")
+	}
+	outputs := map[string]bool{}
+	for i, t := range c.Targets {
+		switch {
+		case len(t.Schemas) == 0:
+			return nil, fmt.Errorf("target %d has no JSON Schema files listed", i)
+		case t.Output == "":
+			return nil, fmt.Errorf("target %d has no output file", i)
+		case outputs[filepath.Clean(t.Output)]:
+			return nil, fmt.Errorf("output file %s is listed in more than one target", t.Output)
+		case t.Package != "" && !token.IsIdentifier(t.Package):
+			return nil, fmt.Errorf("target %d has an invalid package name %q", i, t.Package)
+		}
+		outputs[filepath.Clean(t.Output)] = true
+		for _, filename := range t.Schemas {
+			if filename == "-" {
+				return nil, fmt.Errorf("target %d can't read a JSON Schema from stdin", i)
+			}
+		}
+	}
+	return &c, nil
+}
+
+// generateConfig reads the config file at path and the JSON Schema files (and the documents they
+// refer to, using loader) that it lists, and returns the formatted Go source code for each target
+// (keyed by the path of its output file). The options in the config override opts.
+func generateConfig(loader jsonschema.Loader, path string, opts compiler.Options) (map[string][]byte, error) {
+	c, err := readConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config from %s: %w", path, err)
+	}
+	if len(c.Options) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(c.Options))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&opts); err != nil {
+			return nil, fmt.Errorf("error reading config from %s: invalid options: %w", path, err)
+		}
+	}
+	dir := filepath.Dir(path)
+
+	// Read the JSON Schema files of all targets.
+	targetOf := map[*jsonschema.Schema]*configTarget{}
+	for _, t := range c.Targets {
+		for _, filename := range t.Schemas {
+			filename = filepath.Join(dir, filename)
+			root, err := readSchema(loader, filename)
+			if err != nil {
+				return nil, fmt.Errorf("error reading JSON Schema from %s: %w", filename, err)
+			}
+			if targetOf[root] != nil {
+				return nil, fmt.Errorf("error reading config from %s: JSON Schema file %s is listed in more than one target", path, filename)
+			}
+			targetOf[root] = t
+			t.roots = append(t.roots, root)
+		}
+	}
+
+	// The targets' type names and Go types are added to (and override) those in opts.
+	typeNames := make(map[string]string, len(opts.TypeNames))
+	for location, name := range opts.TypeNames {
+		typeNames[location] = name
+	}
+	goTypes := make(map[string]string, len(opts.GoTypes))
+	for location, goType := range opts.GoTypes {
+		goTypes[location] = goType
+	}
+	for _, t := range c.Targets {
+		for location, name := range t.TypeNames {
+			uri, err := configSchemaURI(loader, dir, location)
+			if err != nil {
+				return nil, fmt.Errorf("error reading config from %s: invalid type name location %q: %w", path, location, err)
+			}
+			typeNames[uri] = name
+		}
+		for location, goType := range t.GoTypes {
+			uri, err := configSchemaURI(loader, dir, location)
+			if err != nil {
+				return nil, fmt.Errorf("error reading config from %s: invalid Go type location %q: %w", path, location, err)
+			}
+			goTypes[uri] = goType
+		}
+	}
+
+	outputs := make(map[string][]byte, len(c.Targets))
+	for _, t := range c.Targets {
+		// Refer to the Go types for the JSON Schema files of other targets in their packages (in
+		// addition to those in opts.ImportPaths).
+		importPaths := make(map[string]string, len(opts.ImportPaths))
+		for prefix, importPath := range opts.ImportPaths {
+			importPaths[prefix] = importPath
+		}
+		for _, other := range c.Targets {
+			if other.ImportPath == "" || other.ImportPath == t.ImportPath {
+				continue
+			}
+			for _, root := range other.roots {
+				importPaths[strings.TrimSuffix(root.BaseURI(), "#")] = other.ImportPath
+			}
+		}
+
+		targetOpts := opts
+		targetOpts.TypeNames = typeNames
+		targetOpts.GoTypes = goTypes
+		targetOpts.ImportPaths = importPaths
+		targetOpts.Loader = loader
+		pkgName := t.Package
+		if pkgName == "" {
+			pkgName = *packageName
+		}
+		output := filepath.Join(dir, t.Output)
+		out, err := generateGo(t.roots, pkgName, targetOpts)
+		if err != nil {
+			return nil, fmt.Errorf("generating %s: %w", output, err)
+		}
+		outputs[output] = out
+	}
+	return outputs, nil
+}
+
+// configSchemaURI returns the location of the schema that is identified in a config by a JSON Schema
+// file path and a JSON Pointer fragment (such as "a.json#/definitions/foo") as a URI (in the form
+// used by compiler.Options.TypeNames).
+func configSchemaURI(loader jsonschema.Loader, dir, location string) (string, error) {
+	filename, fragment, _ := strings.Cut(location, "#")
+	ptr, err := jsonschema.ParsePointer(fragment)
+	if err != nil {
+		return "", err
+	}
+	root, err := readSchema(loader, filepath.Join(dir, filename))
+	if err != nil {
+		return "", err
+	}
+	if _, err := ptr.EvaluateSchema(root); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(root.BaseURI(), "#") + "#" + ptr.URIFragment(), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/compiler"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// writeFiles writes the files (keyed by their path relative to dir) to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	tests := map[string]string{
+		`{"targets": []}`:                                                            "no targets listed",
+		`{"targets": [{"output": "a.go"}]}`:                                          "target 0 has no JSON Schema files listed",
+		`{"targets": [{"schemas": ["a.json"]}]}`:                                     "target 0 has no output file",
+		`{"targets": [{"schemas": ["-"], "output": "a.go"}]}`:                        "target 0 can't read a JSON Schema from stdin",
+		`{"targets": [{"schemas": ["a.json"], "output": "a.go", "package": "a-b"}]}`: `target 0 has an invalid package name "a-b"`,
+		`{"targets": [{"schemas": ["a.json"], "output": "a.go"}, {"schemas": ["b.json"], "output": "./a.go"}]}`: "output file ./a.go is listed in more than one target",
+		`{"targets": [{"schemas": ["a.json"], "output": "a.go", "pkg": "a"}]}`:                                  `json: unknown field "pkg"`,
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := readConfig(path)
+			if err == nil || err.Error() != want {
+				t.Errorf("got error %v, want %q", err, want)
+			}
+		})
+	}
+}
+
+func TestConfigSchemaURI(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.json": `{"definitions": {"b": {"type": "string"}}}`,
+	})
+	loader := jsonschema.NewCachingLoader(jsonschema.FileLoader{})
+
+	got, err := configSchemaURI(loader, dir, "a.json#/definitions/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "a.json")), Fragment: "/definitions/b"}).String()
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, location := range []string{"a.json#/definitions/c", "a.json#definitions", "c.json#/definitions/b"} {
+		if _, err := configSchemaURI(loader, dir, location); err == nil {
+			t.Errorf("%s: got nil error, want error", location)
+		}
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.json": `{
+  "title": "thing",
+  "type": "object",
+  "properties": {"kind": {"$ref": "#/definitions/kind"}, "size": {"$ref": "#/definitions/size"}},
+  "definitions": {
+    "kind": {"type": "object", "properties": {"name": {"type": "string"}}},
+    "size": {"type": "object", "properties": {"width": {"type": "integer"}}}
+  }
+}`,
+		"b.json": `{
+  "title": "box",
+  "type": "object",
+  "properties": {
+    "thing": {"$ref": "a.json"},
+    "kind": {"$ref": "a.json#/definitions/kind"},
+    "size": {"$ref": "a.json#/definitions/size"}
+  }
+}`,
+	})
+	aURI := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "a.json"))}).String()
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{
+  "options": {"TypeNames": {"` + aURI + `#/definitions/kind": "ThingKind"}},
+  "targets": [
+    {"schemas": ["a.json"], "output": "a/a.go", "package": "a", "importPath": "example.com/p/a", "typeNames": {"a.json#/definitions/size": "ThingSize"}},
+    {"schemas": ["b.json"], "output": "b/b.go", "package": "b"}
+  ]
+}`,
+	})
+
+	outputs, err := generateConfig(jsonschema.NewCachingLoader(jsonschema.FileLoader{}), filepath.Join(dir, "config.json"), compiler.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 {
+		t.Fatalf("got %d outputs, want 2", len(outputs))
+	}
+	a, b := string(outputs[filepath.Join(dir, "a/a.go")]), string(outputs[filepath.Join(dir, "b/b.go")])
+
+	// The type names from the options and from the target both apply.
+	for _, want := range []string{"package a\n", "type ThingKind struct", "type ThingSize struct", "type Thing struct"} {
+		if !strings.Contains(a, want) {
+			t.Errorf("a/a.go does not contain %q:\n%s", want, a)
+		}
+	}
+
+	// The other target refers to the Go types in the package with the import path.
+	for _, want := range []string{"package b\n", `"example.com/p/a"`, "*a.Thing", "*a.ThingKind", "*a.ThingSize"} {
+		if !strings.Contains(b, want) {
+			t.Errorf("b/b.go does not contain %q:\n%s", want, b)
+		}
+	}
+	if strings.Contains(b, "type Thing") {
+		t.Errorf("b/b.go contains a Go type for a.json:\n%s", b)
+	}
+}
+
+func TestGenerateConfig_invalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.json":      `{"title": "a", "type": "string"}`,
+		"config.json": `{"options": {"EmitValidate": true}, "targets": [{"schemas": ["a.json"], "output": "a.go"}]}`,
+	})
+	_, err := generateConfig(jsonschema.NewCachingLoader(jsonschema.FileLoader{}), filepath.Join(dir, "config.json"), compiler.Options{})
+	if err == nil || !strings.Contains(err.Error(), "invalid options") {
+		t.Errorf("got error %v, want invalid options error", err)
+	}
+}
@@ -10,17 +10,40 @@ import (
 	"go/ast"
 	"go/format"
 	"go/token"
-	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/sourcegraph/go-jsonschema/compiler"
+	"github.com/sourcegraph/go-jsonschema/internal/diff"
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 var (
 	packageName = flag.String("pkg", "schema", "Go package name to use in emitted source code")
 	outputFile  = flag.String("o", "", "write result to file instead of stdout")
+
+	emitValidateMethods    = flag.Bool("validate", false, "emit a Validate method on each generated type that checks the schema's validation keywords")
+	emitApplyDefaults      = flag.Bool("defaults", false, "emit an ApplyDefaults method on each generated struct type that sets absent fields to their schema's default value")
+	emitEnumTypes          = flag.Bool("enums", false, "emit a named type with a constant for each value for string and integer enums")
+	strictEnumUnmarshaling = flag.Bool("strict-enums", false, "emit an UnmarshalJSON method on each enum type that rejects values not in the enum (requires -enums)")
+	sizedIntegerTypes      = flag.Bool("sized-ints", false, "represent integers by the smallest Go integer type (such as uint8 or int64) that holds all values allowed by minimum and maximum")
+	formats                = flag.String("formats", "", "comma-separated list of string formats to represent by Go types other than string (\"all\" for all supported formats; prefix a format with \"-\" to exclude it)")
+	inferUnions            = flag.Bool("infer-unions", false, "emit a Go union type for each oneOf whose branches are discriminated by a common required property with a distinct const value in each branch")
+	emitUnionTypes         = flag.Bool("unions", false, "emit a Go union type for each anyOf or oneOf whose branches allow distinct kinds of JSON values (such as a string or a boolean)")
+	pointerPolicy          = flag.String("pointers", "", "which optional properties to represent by pointers (\"optional\" for all optional properties; default is only those of struct and other non-builtin types)")
+	typeNamePrefix         = flag.String("type-prefix", "", "prefix to prepend to the name of each generated Go type")
+	conditionalSchemas     = flag.Bool("conditional-schemas", false, "generate Go types for the if/then/else subschemas (which are skipped by default)")
+	builtinTypes           = flag.String("builtin-types", "", "comma-separated list of type=gotype pairs that override the Go builtin type for a JSON Schema type (such as \"number=float32,integer=int64\")")
+	importPaths            = flag.String("import-paths", "", "comma-separated list of prefix=importpath pairs that map a prefix of JSON Schema $ids to the import path of the Go package that holds their Go types (which are referred to instead of generated)")
+	embedSchemas           = flag.Bool("embed-schemas", false, "embed each root JSON Schema in the generated code, with a Schema method on the root schema's Go type that returns it")
+
+	configFile = flag.String("config", "", "read the JSON Schema files, output files, and options from the project config `file` (instead of from arguments and the -o and -pkg flags)")
+
+	check = flag.Bool("check", false, "instead of writing the output files, check that they are up to date (printing a unified diff of the changes to make and exiting with status 1 if not; requires -o or -config)")
+	watch = flag.Bool("watch", false, "after writing the output files, watch the JSON Schema files (and the files they refer to) and regenerate the output files when they change (requires -o or -config)")
 )
 
 func main() {
@@ -28,78 +51,210 @@ func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
 		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler [flags] files...")
+		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler [flags] -config file")
 		fmt.Fprintln(os.Stderr, "Flags:")
 		flag.PrintDefaults()
 	}
-	if flag.NArg() == 0 {
+	if *configFile != "" {
+		if flag.NArg() > 0 || *outputFile != "" {
+			fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: -config can't be used with JSON Schema files or -o (list them in the config file).")
+			os.Exit(2)
+		}
+	} else if flag.NArg() == 0 {
 		fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: no JSON Schema files listed.")
 		fmt.Fprintln(os.Stderr)
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	schemas := make([]*jsonschema.Schema, flag.NArg())
-	for i, filename := range flag.Args() {
-		var err error
-		schemas[i], err = readSchema(filename)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
+	formatsList, err := parseFormats(*formats)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: invalid -formats flag: %s.\n", err)
+		os.Exit(2)
+	}
+
+	builtinTypesMap, err := parseBuiltinTypes(*builtinTypes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: invalid -builtin-types flag: %s.\n", err)
+		os.Exit(2)
+	}
+
+	importPathsMap, err := parseImportPaths(*importPaths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: invalid -import-paths flag: %s.\n", err)
+		os.Exit(2)
+	}
+
+	opts := compiler.Options{
+		EmitValidateMethods:       *emitValidateMethods,
+		EmitApplyDefaultsMethods:  *emitApplyDefaults,
+		EmitEnumTypes:             *emitEnumTypes,
+		StrictEnumUnmarshaling:    *strictEnumUnmarshaling,
+		SizedIntegerTypes:         *sizedIntegerTypes,
+		Formats:                   formatsList,
+		InferTaggedUnionTypes:     *inferUnions,
+		EmitUnionTypes:            *emitUnionTypes,
+		PointerPolicy:             compiler.PointerPolicy(*pointerPolicy),
+		TypeNamePrefix:            *typeNamePrefix,
+		IncludeConditionalSchemas: *conditionalSchemas,
+		BuiltinTypes:              builtinTypesMap,
+		ImportPaths:               importPathsMap,
+		EmbedSchemas:              *embedSchemas,
+		Warn: func(message string) {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: warning: %s.\n", message)
+		},
+	}
+
+	// generateOutputs returns the generated Go source code for each output file (keyed by its
+	// path, or "" for stdout).
+	var generateOutputs func(loader jsonschema.Loader) (map[string][]byte, error)
+	var watchFiles []string // files other than the JSON Schema files to watch for changes
+	if *configFile != "" {
+		generateOutputs = func(loader jsonschema.Loader) (map[string][]byte, error) {
+			return generateConfig(loader, *configFile, opts)
+		}
+		watchFiles = []string{*configFile}
+	} else {
+		generateOutputs = func(loader jsonschema.Loader) (map[string][]byte, error) {
+			out, err := generate(loader, flag.Args(), *packageName, opts)
+			if err != nil {
+				return nil, err
+			}
+			return map[string][]byte{*outputFile: out}, nil
+		}
+	}
+
+	if *check {
+		if *outputFile == "" && *configFile == "" {
+			fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: -check requires -o or -config.")
+			os.Exit(2)
+		}
+		if *watch {
+			fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: -check and -watch can't be used together.")
+			os.Exit(2)
+		}
+	}
+	if *watch {
+		if *outputFile == "" && *configFile == "" {
+			fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: -watch requires -o or -config.")
 			os.Exit(2)
 		}
+		for _, filename := range flag.Args() {
+			if filename == "-" {
+				fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: -watch can't be used to read a JSON Schema from stdin.")
+				os.Exit(2)
+			}
+		}
+		watchAndGenerate(generateOutputs, watchFiles) // never returns
 	}
 
-	decls, imports, err := compiler.Compile(schemas)
+	outputs, err := generateOutputs(jsonschema.NewCachingLoader(jsonschema.FileLoader{}))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: compilation error: %s.\n", err)
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: %s.\n", err)
 		os.Exit(2)
 	}
+
+	upToDate := true
+	for _, path := range sortedKeys(outputs) {
+		out := outputs[path]
+		switch {
+		case *check:
+			changes, err := diffFile(path, out)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: output error: %s.\n", err)
+				os.Exit(2)
+			}
+			if changes != "" {
+				os.Stdout.WriteString(changes)
+				fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: %s is not up to date with the JSON Schemas (run go-jsonschema-compiler without -check to regenerate it).\n", path)
+				upToDate = false
+			}
+		case path == "":
+			os.Stdout.Write(out)
+		default:
+			err := writeFileIfDifferent(path, out)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: output error: %s.\n", err)
+				os.Exit(2)
+			}
+		}
+	}
+	if !upToDate {
+		os.Exit(1)
+	}
+}
+
+// generate reads the JSON Schema files (and the documents they refer to, using loader) and returns
+// the formatted Go source code (in the named package) for them.
+func generate(loader jsonschema.Loader, filenames []string, pkgName string, opts compiler.Options) ([]byte, error) {
+	schemas := make([]*jsonschema.Schema, len(filenames))
+	for i, filename := range filenames {
+		var err error
+		schemas[i], err = readSchema(loader, filename)
+		if err != nil {
+			return nil, fmt.Errorf("error reading JSON Schema from %s: %w", filename, err)
+		}
+	}
+	opts.Loader = loader
+	return generateGo(schemas, pkgName, opts)
+}
+
+// generateGo compiles the JSON Schemas and returns the formatted Go source code (in the named
+// package) for them.
+func generateGo(schemas []*jsonschema.Schema, pkgName string, opts compiler.Options) ([]byte, error) {
+	decls, imports, err := compiler.CompileWithOptions(schemas, opts)
+	if err != nil {
+		return nil, fmt.Errorf("compilation error: %w", err)
+	}
 	var buf bytes.Buffer
 
 	fmt.Fprintln(&buf, "// Code generated by go-jsonschema-compiler. DO NOT EDIT.")
 	fmt.Fprintln(&buf)
 
 	file := &ast.File{
-		Name:    ast.NewIdent(*packageName),
+		Name:    ast.NewIdent(pkgName),
 		Imports: imports,
 		Decls:   decls,
 	}
 	if err := format.Node(&buf, token.NewFileSet(), file); err != nil {
-		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: code formatting error: %s.\n", err)
-		os.Exit(2)
+		return nil, fmt.Errorf("code formatting error: %w", err)
 	}
 	out := buf.Bytes()
 	if !bytes.HasSuffix(out, []byte("\n")) {
 		out = append(out, '\n')
 	}
-
-	if *outputFile == "" {
-		os.Stdout.Write(out)
-	} else {
-		err := writeFileIfDifferent(*outputFile, out)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: output error: %s.\n", err)
-			os.Exit(2)
-		}
-	}
+	return out, nil
 }
 
-func readSchema(filename string) (*jsonschema.Schema, error) {
-	var f io.ReadCloser
+func readSchema(loader jsonschema.Loader, filename string) (*jsonschema.Schema, error) {
 	if filename == "-" {
-		f = os.Stdin
-	} else {
-		var err error
-		f, err = os.Open(filename)
-		if err != nil {
-			return nil, err
-		}
+		// Relative $refs in the schema on stdin are resolved against the working directory.
+		loader = stdinLoader{}
+	}
+
+	// Load the file by its URI so that relative $refs in it are resolved against its location.
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
 	}
-	defer f.Close()
+	uri := &url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return jsonschema.Load(loader, uri.String())
+}
+
+// stdinLoader is a jsonschema.Loader that loads the JSON Schema document from stdin (whatever its
+// URI).
+type stdinLoader struct{}
 
+// Load implements jsonschema.Loader.
+func (stdinLoader) Load(uri *url.URL) (*jsonschema.Schema, error) {
 	var schema *jsonschema.Schema
-	if err := json.NewDecoder(f).Decode(&schema); err != nil {
+	if err := json.NewDecoder(os.Stdin).Decode(&schema); err != nil {
 		return nil, err
 	}
+	if schema == nil {
+		return nil, fmt.Errorf("JSON Schema document is null")
+	}
+	schema.RetrievalURI = uri.String()
 	return schema, nil
 }
 
@@ -118,3 +273,81 @@ func writeFileIfDifferent(path string, data []byte) error {
 
 	return ioutil.WriteFile(path, data, 0666)
 }
+
+// diffFile returns a unified diff that transforms the contents at path (which need not exist) to
+// data, or "" if they are the same.
+func diffFile(path string, data []byte) (string, error) {
+	oldName := path
+	old, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		oldName = "/dev/null"
+	} else if err != nil {
+		return "", err
+	}
+	return diff.Unified(oldName, path, old, data), nil
+}
+
+// parseFormats parses the value of the -formats flag, such as "all,-uuid" or "date-time,uri".
+func parseFormats(value string) ([]string, error) {
+	var formats []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+		case item == "all":
+			formats = append(formats, compiler.SupportedFormats()...)
+		case strings.HasPrefix(item, "-"):
+			exclude := strings.TrimPrefix(item, "-")
+			found := false
+			for i := 0; i < len(formats); i++ {
+				if formats[i] == exclude {
+					formats = append(formats[:i], formats[i+1:]...)
+					i--
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%q is not in the list of formats", exclude)
+			}
+		default:
+			formats = append(formats, item)
+		}
+	}
+	return formats, nil
+}
+
+// parseBuiltinTypes parses the value of the -builtin-types flag, such as "number=float32,integer=int64".
+func parseBuiltinTypes(value string) (map[jsonschema.PrimitiveType]string, error) {
+	builtinTypes := map[jsonschema.PrimitiveType]string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		typ, goType, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not of the form type=gotype", item)
+		}
+		builtinTypes[jsonschema.PrimitiveType(strings.TrimSpace(typ))] = strings.TrimSpace(goType)
+	}
+	return builtinTypes, nil
+}
+
+// parseImportPaths parses the value of the -import-paths flag, such as
+// "https://example.com/schemas/=example.com/project/schema". Because a prefix may contain "=" (but an
+// import path can't), each pair is split at its last "=".
+func parseImportPaths(value string) (map[string]string, error) {
+	importPaths := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.LastIndex(item, "=")
+		if i == -1 {
+			return nil, fmt.Errorf("%q is not of the form prefix=importpath", item)
+		}
+		importPaths[strings.TrimSpace(item[:i])] = strings.TrimSpace(item[i+1:])
+	}
+	return importPaths, nil
+}
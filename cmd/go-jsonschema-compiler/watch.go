@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// watchInterval is how often the watched files are checked for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watchAndGenerate generates the output files, and then regenerates them each time one of the
+// files read during the previous generation (the JSON Schema files and the documents they refer to)
+// or one of the other files changes. Errors are reported but do not stop watching. It never returns.
+//
+// The output files are written using writeFileIfDifferent, so they are only modified if the
+// generated code changed.
+func watchAndGenerate(generateOutputs func(jsonschema.Loader) (map[string][]byte, error), otherFiles []string) {
+	for {
+		loader := &recordingLoader{loader: jsonschema.FileLoader{}, files: map[string]fileState{}}
+		for _, path := range otherFiles {
+			loader.files[path] = statFile(path)
+		}
+		if outputs, err := generateOutputs(jsonschema.NewCachingLoader(loader)); err != nil {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: %s.\n", err)
+		} else {
+			for _, path := range sortedKeys(outputs) {
+				if err := writeFileIfDifferent(path, outputs[path]); err != nil {
+					fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: output error: %s.\n", err)
+				}
+			}
+		}
+		waitForChange(loader.files)
+	}
+}
+
+// waitForChange returns when the state of one of the files differs from its given state.
+func waitForChange(files map[string]fileState) {
+	for {
+		time.Sleep(watchInterval)
+		for path, state := range files {
+			if statFile(path) != state {
+				return
+			}
+		}
+	}
+}
+
+// fileState is the state of a file that is checked to determine whether it has changed.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime int64 // in nanoseconds since the Unix epoch
+}
+
+func statFile(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: fi.Size(), modTime: fi.ModTime().UnixNano()}
+}
+
+// recordingLoader is a Loader that records the state of each local file (before it is read) that
+// it loads (including files that fail to load), so that they can be watched for changes.
+type recordingLoader struct {
+	loader jsonschema.Loader
+
+	mu    sync.Mutex
+	files map[string]fileState // key is the file path
+}
+
+// Load implements jsonschema.Loader.
+func (l *recordingLoader) Load(uri *url.URL) (*jsonschema.Schema, error) {
+	if uri.Scheme == "file" {
+		l.mu.Lock()
+		l.files[uri.Path] = statFile(uri.Path)
+		l.mu.Unlock()
+	}
+	return l.loader.Load(uri)
+}
@@ -5,23 +5,193 @@ import (
 	"go/ast"
 	"go/token"
 	"sort"
+	"strings"
 
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
+// Options configures the compiler. The zero value is the default configuration.
+type Options struct {
+	// EmitValidateMethods causes a Validate method to be emitted for each generated type. The
+	// method checks the value against the validation keywords (such as minLength, pattern,
+	// maximum, and required) of the schema that describes the type. So that absent values are
+	// distinguishable from zero values, optional properties whose zero value is invalid (such as a
+	// string with a minLength of 1) are represented by pointers.
+	EmitValidateMethods bool
+
+	// EmitApplyDefaultsMethods causes an ApplyDefaults method to be emitted for each generated
+	// struct type. The method sets each absent field whose property has a "default" to the default
+	// value (and applies the defaults of nested struct values). So that absent values are
+	// distinguishable from zero values, optional properties that have a default are represented by
+	// pointers (unless their Go type is a slice, map, or interface type). The defaults of required
+	// properties are ignored (and reported by Warn). A default value that is not valid for the Go
+	// type of its field is a compilation error.
+	EmitApplyDefaultsMethods bool
+
+	// EmitEnumTypes causes a named Go type (with an exported constant for each value) to be
+	// emitted for each schema whose "enum" values are all strings or all integers. Struct fields
+	// for such schemas use the named type instead of a builtin Go type.
+	EmitEnumTypes bool
+
+	// StrictEnumUnmarshaling causes an UnmarshalJSON method that rejects values not in the enum to
+	// be emitted for each Go enum type. It has no effect unless EmitEnumTypes is set.
+	StrictEnumUnmarshaling bool
+
+	// SizedIntegerTypes causes integer schemas to be represented by the smallest fixed-size Go
+	// integer type (such as uint8 or int64) that holds all values allowed by the schema's minimum
+	// and maximum, instead of int.
+	SizedIntegerTypes bool
+
+	// Formats lists the values of the "format" keyword for which string schemas are represented
+	// by a Go type other than string (for example, time.Time for "date-time"). Formats not listed
+	// are represented by string. See SupportedFormats for the formats that may be listed.
+	//
+	// The "ipv4" and "ipv6" formats are both represented by netip.Addr, which holds addresses of
+	// either family; only the Validate methods (see EmitValidateMethods) check the family.
+	Formats []string
+
+	// InferTaggedUnionTypes causes a Go union type (as for the !go.taggedUnionType extension) to be
+	// emitted for each object schema whose oneOf branches are object schemas with a common required
+	// property that has a distinct string value ("const" or single-valued "enum") in each branch.
+	// Each branch must be a $ref or have a title (so that the Go types of the branches have
+	// distinct names).
+	InferTaggedUnionTypes bool
+
+	// EmitUnionTypes causes a Go union type (a struct with a field for each branch, and
+	// MarshalJSON and UnmarshalJSON methods that use the field for the kind of JSON value) to be
+	// emitted for each schema whose anyOf (or oneOf) branches allow JSON values of distinct kinds
+	// (string, number, boolean, array, and object). Schemas whose branches are ambiguous are
+	// represented by any (and reported by Warn).
+	EmitUnionTypes bool
+
+	// PointerPolicy determines which optional properties are represented by struct fields of a
+	// pointer type. See the PointerPolicy constants.
+	PointerPolicy PointerPolicy
+
+	// TypeNamePrefix is prepended to the name of each emitted Go type (and each enum constant). It
+	// must be empty or a valid Go identifier; if it begins with a lowercase letter, the emitted
+	// types are unexported.
+	TypeNamePrefix string
+
+	// IncludeConditionalSchemas causes Go types to be emitted for the "if", "then", and "else"
+	// subschemas. These are skipped by default because they are usually only used for validation
+	// (not for defining types).
+	IncludeConditionalSchemas bool
+
+	// BuiltinTypes maps a JSON Schema primitive type to the Go builtin type that represents it,
+	// overriding the default (bool, float64, int, or string). The "boolean" type may be mapped
+	// only to bool, "number" to float32 or float64, "integer" to any Go integer type, and "string"
+	// to string. For integers, SizedIntegerTypes and the !go.integerType extension take precedence.
+	BuiltinTypes map[jsonschema.PrimitiveType]string
+
+	// EmbedSchemas causes the canonical JSON encoding of each root schema to be emitted as a
+	// constant (named <type>SchemaJSON), with a Schema method on the root schema's Go type that
+	// returns it as a *jsonschema.Schema. The constant is parsed only once, so the Schema method
+	// always returns the same *jsonschema.Schema, which must not be modified. Root schemas that
+	// have no Go named type (or whose Go type has a Schema field) are not embedded (and are
+	// reported by Warn).
+	EmbedSchemas bool
+
+	// TypeNames maps the location of a schema to the name of the Go named type emitted for it,
+	// overriding the name derived from its title or location (as for the !go.typeName extension).
+	// The location is a URI consisting of the base URI of the schema's root (see
+	// jsonschema.Schema.BaseURI; empty if it has none) and a JSON Pointer fragment (such as
+	// "https://example.com/a.json#/definitions/foo"). Schemas that are not represented by a Go
+	// named type are unaffected.
+	TypeNames map[string]string
+
+	// ImportPaths maps a prefix of the base URI of root schemas (see jsonschema.Schema.BaseURI) to
+	// the import path of the Go package that holds the Go types for them, which were generated
+	// separately (with the same options). No Go types are emitted for such root schemas (whether
+	// passed to CompileWithOptions or loaded by Loader); instead, $refs to them and their
+	// subschemas are represented by the types in the Go package, qualified by its name (the last
+	// element of the import path, or the one before it if the last is a major version suffix such
+	// as "v2"). The longest matching prefix is used.
+	//
+	// A $ref to a document that is neither passed to CompileWithOptions nor loadable, but whose URI
+	// has a prefix in ImportPaths, is represented by a type in the Go package whose name is given by
+	// TypeNames or is derived from the $ref's URI (from the last name in its JSON Pointer fragment,
+	// such as "Foo" for "a.json#/definitions/foo", or else from its file name, such as "A"). Because
+	// nothing else is known about such types, struct fields of them are pointers, and they are not
+	// validated or given defaults. A $ref to the JSON Schema meta-schema (such as
+	// "http://json-schema.org/draft-07/schema#") is represented by jsonschema.Schema unless its URI
+	// has a prefix in ImportPaths.
+	ImportPaths map[string]string
+
+	// GoTypes maps the location of a schema (as for TypeNames, or the "$id" of the schema) to an
+	// existing Go named type that represents it: the import path of its package, a ".", and its name
+	// (such as "time.Duration" or "example.com/project/repo.Repo"), or only its name for a type in
+	// the generated package. No Go type is emitted for the schema (but Go types are still emitted
+	// for its subschemas), and values of the Go type are not validated or given defaults. It
+	// overrides the !go.import and !go.type extensions, which bind a schema to a Go type in the same
+	// way.
+	GoTypes map[string]string
+
+	// Loader, if set, is used to load the documents referred to by $refs that are not among the
+	// schemas passed to CompileWithOptions (see jsonschema.LoadReferences). Go types are also
+	// generated for the loaded documents.
+	Loader jsonschema.Loader `json:"-"`
+
+	// Warn, if set, is called for each schema that the compiler can't represent as precisely as
+	// the options request (for example, an enum whose values are of multiple types).
+	Warn func(message string) `json:"-"`
+}
+
+// PointerPolicy determines which optional properties are represented by struct fields of a pointer
+// type (so that an absent property is distinguishable from one whose value is the Go zero value).
+// Required properties are never represented by pointers (unless the !go.pointer extension is set).
+type PointerPolicy string
+
+const (
+	// PointerPolicyDefault uses pointers for optional properties of struct types and of other types
+	// that are not Go builtin types (such as time.Time). It is the zero value.
+	PointerPolicyDefault PointerPolicy = ""
+
+	// PointerPolicyOptional uses pointers for all optional properties, including those of Go
+	// builtin types and enum types. Properties represented by slices, maps, and interfaces are
+	// never pointers.
+	PointerPolicyOptional PointerPolicy = "optional"
+)
+
 // Compile generates Go declarations for types that hold values described by the JSON Schemas.
 //
+// It is equivalent to CompileWithOptions with the zero Options.
+func Compile(schemas []*jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
+	return CompileWithOptions(schemas, Options{})
+}
+
+// CompileWithOptions generates Go declarations for types that hold values described by the JSON
+// Schemas.
+//
 // 1. Parse (per-schema)
 // 2. Resolve references (all schemas)
-// 3. Generate code (per-schema)
-func Compile(schemas []*jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
+// 3. Assign Go type names (all schemas)
+// 4. Generate code (per-schema)
+func CompileWithOptions(schemas []*jsonschema.Schema, opts Options) ([]ast.Decl, []*ast.ImportSpec, error) {
+	if err := opts.validate(); err != nil {
+		return nil, nil, err
+	}
+	if opts.Loader != nil {
+		loader := &importPathsLoader{loader: opts.Loader, opts: opts, unavailable: map[*jsonschema.Schema]struct{}{}}
+		loaded, err := jsonschema.LoadReferences(loader, schemas)
+		if err != nil {
+			return nil, nil, err
+		}
+		schemas = schemas[:len(schemas):len(schemas)]
+		for _, schema := range loaded {
+			if _, ok := loader.unavailable[schema]; !ok {
+				schemas = append(schemas, schema)
+			}
+		}
+	}
+
 	//
 	// Step 1: Parse (per-schema)
 	//
 	locationsByRoot := make(schemaLocationsByRoot, len(schemas))
 	for _, root := range schemas {
 		var err error
-		locationsByRoot[root], err = parseSchema(root)
+		locationsByRoot[root], err = parseSchema(root, opts)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -30,18 +200,36 @@ func Compile(schemas []*jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error
 	//
 	// Step 2: Resolve references (all schemas together)
 	//
-	resolutions, err := resolveReferences(locationsByRoot)
+	resolutions, externalTypes, err := resolveReferences(locationsByRoot, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	goTypes, err := boundGoTypes(locationsByRoot, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	for schema, typ := range goTypes {
+		externalTypes[schema] = typ
+	}
+
+	//
+	// Step 3: Assign unique Go type names (all schemas together)
+	//
+	typeNames, err := assignGoNames(locationsByRoot, resolutions, externalTypes, opts)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	//
-	// Step 3: Generate code (per-schema)
+	// Step 4: Generate code (per-schema)
 	//
 	var allDecls []ast.Decl
 	var allImports []*ast.ImportSpec
-	for _, schemas := range locationsByRoot {
-		decls, imports, err := generateDecls(schemas, resolutions, locationsByRoot)
+	for root, schemas := range locationsByRoot {
+		if opts.importPathForRoot(root) != "" {
+			continue // the Go types are in another package
+		}
+		decls, imports, err := generateDecls(schemas, resolutions, externalTypes, locationsByRoot, typeNames, opts)
 		if err != nil {
 			return nil, nil, fmt.Errorf("generating decls: %w", err)
 		}
@@ -53,7 +241,18 @@ func Compile(schemas []*jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error
 		name := func(k int) string {
 			switch d := allDecls[k].(type) {
 			case *ast.GenDecl:
-				return d.Specs[0].(*ast.TypeSpec).Name.Name
+				switch spec := d.Specs[0].(type) {
+				case *ast.TypeSpec:
+					return spec.Name.Name
+				case *ast.ValueSpec:
+					// Sort constants and variables after the type they belong to.
+					if spec.Type != nil {
+						return derefPtrType(spec.Type).Name
+					}
+					return spec.Names[0].Name
+				default:
+					panic(fmt.Sprintf("unhandled %T", spec))
+				}
 			case *ast.FuncDecl:
 				return derefPtrType(d.Recv.List[0].Type).Name
 			default:
@@ -83,6 +282,44 @@ func Compile(schemas []*jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error
 	return allDecls, allImports, nil
 }
 
+// validate reports an error if opts is invalid.
+func (opts Options) validate() error {
+	for _, format := range opts.Formats {
+		if _, ok := formatGoTypes[jsonschema.Format(format)]; !ok {
+			return fmt.Errorf("unsupported format %q in options (supported formats are: %s)", format, strings.Join(SupportedFormats(), ", "))
+		}
+	}
+	switch opts.PointerPolicy {
+	case PointerPolicyDefault, PointerPolicyOptional:
+	default:
+		return fmt.Errorf("invalid pointer policy %q in options", opts.PointerPolicy)
+	}
+	if opts.TypeNamePrefix != "" && !token.IsIdentifier(opts.TypeNamePrefix) {
+		return fmt.Errorf("invalid type name prefix %q in options (must be a Go identifier)", opts.TypeNamePrefix)
+	}
+	for typ, goType := range opts.BuiltinTypes {
+		if !isAllowedBuiltinType(typ, goType) {
+			return fmt.Errorf("invalid Go builtin type %q for JSON Schema type %q in options", goType, typ)
+		}
+	}
+	for _, name := range opts.TypeNames {
+		if !token.IsIdentifier(name) {
+			return fmt.Errorf("invalid Go type name %q in options", name)
+		}
+	}
+	for prefix, importPath := range opts.ImportPaths {
+		if !token.IsIdentifier(packageNameForImportPath(importPath)) {
+			return fmt.Errorf("invalid import path %q for $id prefix %q in options (its last element must be a Go package name)", importPath, prefix)
+		}
+	}
+	for location, goType := range opts.GoTypes {
+		if err := parseGoType(goType).check(); err != nil {
+			return fmt.Errorf("invalid Go type %q for schema %q in options (%s)", goType, location, err)
+		}
+	}
+	return nil
+}
+
 type schemaLocator interface {
 	locateSchema(schema *jsonschema.Schema) (root *jsonschema.Schema, location *schemaLocation)
 }
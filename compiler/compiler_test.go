@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
@@ -34,6 +35,15 @@ func TestCompiler(t *testing.T) {
 	}
 }
 
+// optionsFile is the name of the (optional) file in a test case directory that contains the JSON
+// encoding of the Options to compile the test case's schemas with.
+const optionsFile = "options.json"
+
+// externalDir is the name of the (optional) subdirectory of a test case directory that contains
+// the documents that Options.Loader loads. The document with the URI
+// https://example.com/external/foo.json is read from the file external/foo.json.
+const externalDir = "external"
+
 func testCompiler(t *testing.T, dir string) {
 	entries, err := ioutil.ReadDir(dir)
 	if err != nil {
@@ -41,6 +51,7 @@ func testCompiler(t *testing.T, dir string) {
 	}
 
 	var schemas []*jsonschema.Schema
+	var opts Options
 	goFiles := map[string][]byte{}
 	for _, entry := range entries {
 		if entry.Mode().IsDir() {
@@ -50,19 +61,30 @@ func testCompiler(t *testing.T, dir string) {
 		if err != nil {
 			t.Fatalf("read %s: %s", entry.Name(), err)
 		}
-		switch filepath.Ext(entry.Name()) {
-		case ".json":
+		switch ext := filepath.Ext(entry.Name()); {
+		case entry.Name() == optionsFile:
+			if err := json.Unmarshal(data, &opts); err != nil {
+				t.Fatalf("unmarshal %s: %s", entry.Name(), err)
+			}
+		case ext == ".json":
 			var schema jsonschema.Schema
 			if err := json.Unmarshal(data, &schema); err != nil {
 				t.Fatalf("unmarshal %s: %s", entry.Name(), err)
 			}
 			schemas = append(schemas, &schema)
-		case ".go":
+		case ext == ".go":
 			goFiles[entry.Name()] = data
 		}
 	}
 
-	decls, imports, err := Compile(schemas)
+	if fi, err := os.Stat(filepath.Join(dir, externalDir)); err == nil && fi.IsDir() {
+		opts.Loader = jsonschema.FSLoader{
+			FS:   os.DirFS(filepath.Join(dir, externalDir)),
+			Base: "https://example.com/" + externalDir + "/",
+		}
+	}
+
+	decls, imports, err := CompileWithOptions(schemas, opts)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -104,3 +126,91 @@ func diff(path string, data []byte) (string, error) {
 	}
 	return string(data), err
 }
+
+func TestCompileWithOptions_invalidOptions(t *testing.T) {
+	tests := map[string]Options{
+		"pointer policy":     {PointerPolicy: "sometimes"},
+		"type name prefix":   {TypeNamePrefix: "A-"},
+		"builtin type":       {BuiltinTypes: map[jsonschema.PrimitiveType]string{jsonschema.NumberType: "int"}},
+		"builtin type (key)": {BuiltinTypes: map[jsonschema.PrimitiveType]string{jsonschema.ObjectType: "string"}},
+		"go type":            {GoTypes: map[string]string{"#": "example.com/p.T-1"}},
+		"go type (package)":  {GoTypes: map[string]string{"#": "example.com/go-p.T"}},
+	}
+	schema := &jsonschema.Schema{Type: jsonschema.PrimitiveTypeList{jsonschema.StringType}}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := CompileWithOptions([]*jsonschema.Schema{schema}, opts); err == nil {
+				t.Error("got nil error, want error for invalid options")
+			}
+		})
+	}
+}
+
+type reflectedConfig struct {
+	ReflectedBase
+	Name     string            `json:"name"`
+	Port     uint16            `json:"port,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Child    *ReflectedChild   `json:"child,omitempty"`
+	Children []*ReflectedChild `json:"children"`
+	Created  time.Time         `json:"created"`
+	Extra    any               `json:"extra,omitempty"`
+}
+
+type ReflectedBase struct {
+	ID int64 `json:"id"`
+}
+
+type ReflectedChild struct {
+	Parent *ReflectedChild `json:"parent,omitempty"`
+	Score  float64         `json:"score"`
+}
+
+// TestCompile_reflectedSchema tests that the Go types for the schema returned by jsonschema.Reflect
+// are equivalent to the reflected Go types.
+func TestCompile_reflectedSchema(t *testing.T) {
+	data, err := json.Marshal(jsonschema.Reflect(reflectedConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	decls, imports, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{Formats: []string{"date-time"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	file := &ast.File{Name: ast.NewIdent("p"), Imports: imports, Decls: decls}
+	if err := format.Node(&buf, token.NewFileSet(), file); err != nil {
+		t.Fatal(err)
+	}
+	want := `package p
+
+import "time"
+
+type ReflectedBase struct {
+	Id int64 ` + "`json:\"id\"`" + `
+}
+type ReflectedChild struct {
+	Parent *ReflectedChild ` + "`json:\"parent,omitempty\"`" + `
+	Score  float64         ` + "`json:\"score\"`" + `
+}
+type ReflectedConfig struct {
+	ReflectedBase
+	Child    *ReflectedChild   ` + "`json:\"child,omitempty\"`" + `
+	Children []*ReflectedChild ` + "`json:\"children\"`" + `
+	Created  time.Time         ` + "`json:\"created\"`" + `
+	Extra    any               ` + "`json:\"extra,omitempty\"`" + `
+	Labels   map[string]string ` + "`json:\"labels,omitempty\"`" + `
+	Name     string            ` + "`json:\"name\"`" + `
+	Port     uint16            ` + "`json:\"port,omitempty\"`" + `
+	Tags     []string          ` + "`json:\"tags,omitempty\"`" + `
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\n\nwant\n%s", got, want)
+	}
+}
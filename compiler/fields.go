@@ -4,11 +4,18 @@ import (
 	"bytes"
 	"go/ast"
 	"go/printer"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 type field struct {
 	GoName, JSONName string
 	*ast.Field
+
+	schema   *jsonschema.Schema   // the property's schema (or the embedded type's schema)
+	schemas  []*jsonschema.Schema // all of the property's schemas (more than one if several allOf branches define it)
+	required bool                 // whether the property is required
+	embedded bool                 // whether the field is an embedded struct type (and JSONName is empty)
 }
 
 func (f field) GoType() string {
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
-	"sort"
 	"strconv"
 
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
@@ -14,25 +13,44 @@ import (
 
 // generateDecls returns Go type declarations for the schemas, which are all in the same root JSON
 // Schema.
-func generateDecls(schemas map[*jsonschema.Schema]schemaLocation, resolutions map[*jsonschema.Schema]*jsonschema.Schema, schemaLocator schemaLocator) ([]ast.Decl, []*ast.ImportSpec, error) {
-	g := generator{schemas: schemas, resolutions: resolutions, schemaLocator: schemaLocator}
+func generateDecls(schemas map[*jsonschema.Schema]schemaLocation, resolutions map[*jsonschema.Schema]*jsonschema.Schema, externalTypes map[*jsonschema.Schema]externalType, schemaLocator schemaLocator, typeNames map[*jsonschema.Schema]string, opts Options) ([]ast.Decl, []*ast.ImportSpec, error) {
+	g := generator{schemas: schemas, resolutions: resolutions, externalTypes: externalTypes, schemaLocator: schemaLocator, typeNames: typeNames, opts: opts}
+	g.markMergedAllOfBranches()
 	var allDecls []ast.Decl
 	var allImports []*ast.ImportSpec
-	for schema := range schemas {
+	for schema, location := range schemas {
 		decls, imports, err := g.emit(schema)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to emit decl for schema: %w", err)
 		}
 		allDecls = append(allDecls, decls...)
 		allImports = append(allImports, imports...)
+
+		if opts.EmbedSchemas && len(location.rel) == 0 {
+			decls, imports, err := g.emitEmbeddedSchema(schema)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to emit embedded schema: %w", err)
+			}
+			allDecls = append(allDecls, decls...)
+			allImports = append(allImports, imports...)
+		}
 	}
+	decls, imports := g.patternVarDecls()
+	allDecls = append(allDecls, decls...)
+	allImports = append(allImports, imports...)
 	return allDecls, allImports, nil
 }
 
 type generator struct {
 	schemas       map[*jsonschema.Schema]schemaLocation     // for the current root schema only
 	resolutions   map[*jsonschema.Schema]*jsonschema.Schema // for all schemas in scope
+	externalTypes map[*jsonschema.Schema]externalType       // see resolveReferences and boundGoTypes
 	schemaLocator schemaLocator
+	typeNames     map[*jsonschema.Schema]string // for all schemas in scope (see assignGoNames)
+	opts          Options
+
+	mergedAllOfBranches map[*jsonschema.Schema]struct{} // see markMergedAllOfBranches
+	patterns            map[string][]string             // the patterns used by each Go type (see patternVar)
 }
 
 var anyType = &ast.Ident{Name: "any"}
@@ -40,11 +58,34 @@ var anyType = &ast.Ident{Name: "any"}
 // emit returns the declaration for the Go type for schema, or nil if no declaration is needed (such
 // as when schema is represented by a builtin Go type).
 func (g *generator) emit(schema *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
-	if schema.Go != nil && schema.Go.TaggedUnionType {
+	if g.isExternal(schema) {
+		return nil, nil, nil // represented by an existing Go type (see Options.GoTypes)
+	}
+	if g.isTupleType(schema) {
+		return g.emitTupleType(schema)
+	}
+	if g.isTaggedUnionType(schema) {
 		return g.emitTaggedUnionType(schema)
+	} else if g.opts.InferTaggedUnionTypes && len(schema.OneOf) > 0 && (schema.Go == nil || schema.Go.TypeName == "") {
+		if _, err := g.findTaggedUnion(schema); err == nil {
+			g.warnf("oneOf at %q has a discriminant property, but no Go union type will be emitted for it because not all of its branches are $refs or have a title", jsonschema.EncodeReferenceTokens(g.schemas[schema].rel))
+		}
+	}
+	if u, err := g.findUnion(schema); err != nil {
+		g.warnf("%s at %q can't be represented by a Go union type (%s), so it will be represented by %s", unionKeyword(schema), jsonschema.EncodeReferenceTokens(g.schemas[schema].rel), err, anyType.Name)
+	} else if u != nil {
+		return g.emitUnionType(schema, u)
+	}
+	if typ, ok := g.enumType(schema); ok {
+		return g.emitEnumType(schema, typ)
+	} else if g.opts.EmitEnumTypes && len(schema.Enum) > 0 && (schema.Go == nil || schema.Go.TypeName == "") {
+		g.warnf("enum at %q has values of multiple or unsupported types, so no Go enum type will be emitted for it", jsonschema.EncodeReferenceTokens(g.schemas[schema].rel))
 	}
 
-	needsNamedGoType := isTypeOrNull(schema, jsonschema.ObjectType) && schema.Properties != nil
+	if _, ok := g.mergedAllOfBranches[schema]; ok {
+		return nil, nil, nil // merged into the Go struct type for the allOf schema
+	}
+	needsNamedGoType := g.isStructType(schema)
 	if !needsNamedGoType {
 		return nil, nil, nil
 	}
@@ -52,27 +93,113 @@ func (g *generator) emit(schema *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSp
 	return g.emitStructType(schema)
 }
 
+// emitsNamedType reports whether emit returns the declaration of a Go named type for schema.
+func (g *generator) emitsNamedType(schema *jsonschema.Schema) bool {
+	if g.isExternal(schema) {
+		return false
+	}
+	if g.isTupleType(schema) || g.isTaggedUnionType(schema) || g.isUnionType(schema) {
+		return true
+	}
+	if _, ok := g.enumType(schema); ok {
+		return true
+	}
+	if _, ok := g.mergedAllOfBranches[schema]; ok {
+		return false
+	}
+	return g.isStructType(schema)
+}
+
 func (g *generator) emitStructType(schema *jsonschema.Schema) (decls []ast.Decl, imports []*ast.ImportSpec, err error) {
-	// Sort properties deterministically (by name).
-	names := make([]string, 0, len(*schema.Properties))
-	for name := range *schema.Properties {
-		names = append(names, name)
+	fields, imports, err := g.structFields(schema)
+	if err != nil {
+		return nil, nil, err
 	}
-	sort.Strings(names)
 
-	// Create a field for each property.
-	fields := make([]field, len(names))
-	for i, name := range names {
-		prop := (*schema.Properties)[name]
+	goName, err := g.goNameForSchema(schema, g.schemas[schema])
+	if err != nil {
+		return nil, nil, err
+	}
+	typeSpec := &ast.TypeSpec{
+		Name: ast.NewIdent(goName),
+		Type: &ast.StructType{Fields: &ast.FieldList{List: astFields(fields)}},
+	}
+	decls = append(decls, &ast.GenDecl{
+		Doc:   docForSchema(schema, goName),
+		Tok:   token.TYPE,
+		Specs: []ast.Spec{typeSpec},
+	})
 
-		typeExpr, fieldImports, err := g.expr(prop)
+	// If the JSON Schema object type also allows additionalProperties or has patternProperties, then
+	// support marshaling and unmarshaling those (see the object-with-props and pattern-properties
+	// test cases).
+	if hasExtraPropertiesFields(schema) {
+		extraFields, decls1, imports1, err := g.emitStructAdditionalField(schema, goName, fields)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get type expression for property %q: %w", name, err)
+			return nil, nil, fmt.Errorf("failed to emit decl for object schema with additionalProperties or patternProperties: %w", err)
+		}
+		typeSpec.Type.(*ast.StructType).Fields.List = append(typeSpec.Type.(*ast.StructType).Fields.List, extraFields...)
+		decls = append(decls, decls1...)
+		imports = append(imports, imports1...)
+	}
+
+	if g.opts.EmitValidateMethods {
+		if name, ok := g.propertyForField(schema, "Validate"); ok {
+			g.warnf("%s has a property %q, which conflicts with the Validate method, so no Validate method will be emitted for it", goName, name)
+		} else {
+			validateDecl, imports1, err := g.emitStructValidateMethod(schema, goName, fields)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to emit Validate method for %s: %w", goName, err)
+			}
+			decls = append(decls, validateDecl)
+			imports = append(imports, imports1...)
+		}
+	}
+
+	if g.opts.EmitApplyDefaultsMethods {
+		if name, ok := g.propertyForField(schema, "ApplyDefaults"); ok {
+			g.warnf("%s has a property %q, which conflicts with the ApplyDefaults method, so no ApplyDefaults method will be emitted for it", goName, name)
+		} else {
+			applyDefaultsDecl, err := g.emitApplyDefaultsMethod(schema, goName, fields)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to emit ApplyDefaults method for %s: %w", goName, err)
+			}
+			decls = append(decls, applyDefaultsDecl)
 		}
-		imports = append(imports, fieldImports...)
+	}
+
+	return decls, imports, nil
+}
+
+// structFields returns the fields of the Go struct type for schema (other than the fields for
+// additionalProperties and patternProperties).
+func (g *generator) structFields(schema *jsonschema.Schema) (fields []field, imports []*ast.ImportSpec, err error) {
+	properties, required, embeds, err := g.structMembers(schema)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	// Create an embedded field for each allOf branch that refers to a Go struct type.
+	fields = make([]field, 0, len(embeds)+len(properties))
+	for _, embed := range embeds {
+		imports = append(imports, embed.imports...)
+		fields = append(fields, field{
+			GoName:   embed.name,
+			Field:    &ast.Field{Type: embed.typeExpr},
+			schema:   embed.schema,
+			embedded: true,
+		})
+	}
+
+	// Create a field for each property (sorted deterministically by name).
+	for _, name := range sortedKeys(properties) {
+		prop := properties[name].schemas[0]
+		typeExpr := properties[name].typeExpr
+		imports = append(imports, properties[name].imports...)
+
+		_, isRequired := required[name]
 		var jsonStructTagExtra string
-		if !schema.IsRequiredProperty(name) {
+		if !isRequired {
 			// In Go, a pointer-to-{array,map,interface}-type doesn't add (necessary) expressiveness for our use
 			// case vs. just an {array,map,interface} type.
 			_, isPtrToArray := typeExpr.(*ast.ArrayType)
@@ -80,14 +207,19 @@ func (g *generator) emitStructType(schema *jsonschema.Schema) (decls []ast.Decl,
 			_, isPtrToInterface := typeExpr.(*ast.InterfaceType)
 			ident, isIdent := typeExpr.(*ast.Ident)
 			isPtrToAny := isIdent && ident.Name == "any"
-			if (!isPtrToArray && !isPtrToMap && !isPtrToInterface && !isPtrToAny && !isBasicType(typeExpr)) || forceGoPointer(prop) {
+			_, isEnumType := g.enumType(g.resolve(prop))
+			usePointer := !isPtrToArray && !isPtrToMap && !isPtrToInterface && !isPtrToAny
+			if g.opts.PointerPolicy != PointerPolicyOptional {
+				usePointer = usePointer && ((!isBasicType(typeExpr) && !isEnumType) || g.hasAppliedDefault(properties[name].schemas...) || g.zeroValueIsInvalid(properties[name].schemas...))
+			}
+			if usePointer || forceGoPointer(prop) {
 				typeExpr = &ast.StarExpr{X: typeExpr}
 			}
 			jsonStructTagExtra = ",omitempty"
 		}
 
 		goName := toGoName(name, "Property_")
-		fields[i] = field{
+		fields = append(fields, field{
 			GoName:   goName,
 			JSONName: name,
 			Field: &ast.Field{
@@ -99,44 +231,39 @@ func (g *generator) emitStructType(schema *jsonschema.Schema) (decls []ast.Decl,
 					Value: fmt.Sprintf("`json:%q`", name+jsonStructTagExtra),
 				},
 			},
-		}
+			schema:   prop,
+			schemas:  properties[name].schemas,
+			required: isRequired,
+		})
 	}
 
-	goName, err := goNameForSchema(schema, g.schemas[schema])
+	return fields, imports, nil
+}
+
+// propertyForField returns the name of the property of the Go struct type for schema whose field
+// is named goName, if any. A method with that name can't be declared on the Go struct type.
+func (g *generator) propertyForField(schema *jsonschema.Schema, goName string) (string, bool) {
+	properties, _, _, err := g.structMembers(schema)
 	if err != nil {
-		return nil, nil, err
+		return "", false // reported when the Go struct type is emitted
 	}
-	typeSpec := &ast.TypeSpec{
-		Name: ast.NewIdent(goName),
-		Type: &ast.StructType{Fields: &ast.FieldList{List: astFields(fields)}},
-	}
-	decls = append(decls, &ast.GenDecl{
-		Doc:   docForSchema(schema, goName),
-		Tok:   token.TYPE,
-		Specs: []ast.Spec{typeSpec},
-	})
-
-	// If the JSON Schema object type also allows additionalProperties, then support marshaling and
-	// unmarshaling those (see the object-with-props test case).
-	if schema.AdditionalProperties != nil && !schema.AdditionalProperties.IsNegated {
-		addlField, decls1, imports1, err := g.emitStructAdditionalField(schema, goName, fields)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to emit decl for object schema with additionalProperties: %w", err)
+	for _, name := range sortedKeys(properties) {
+		if toGoName(name, "Property_") == goName {
+			return name, true
 		}
-		typeSpec.Type.(*ast.StructType).Fields.List = append(typeSpec.Type.(*ast.StructType).Fields.List, addlField)
-		decls = append(decls, decls1...)
-		imports = append(imports, imports1...)
-
 	}
-
-	return decls, imports, nil
+	return "", false
 }
 
 // expr returns the Go expression AST node that refers to the Go type (builtin or named) for schema,
 // as well as any Go import statements that must be added to the file containing this Go expression.
 func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec, error) {
-	if schema == metaSchemaSentinel {
-		return &ast.SelectorExpr{X: ast.NewIdent("jsonschema"), Sel: ast.NewIdent("Schema")}, importSpecs("github.com/sourcegraph/go-jsonschema/jsonschema"), nil
+	if typ, ok := g.externalTypes[schema]; ok {
+		if typ.importPath == "" {
+			return ast.NewIdent(typ.name), nil, nil
+		}
+		expr, imports := qualifiedTypeExpr(typ.importPath, typ.name)
+		return expr, imports, nil
 	}
 
 	// Handle $ref to another schema.
@@ -146,33 +273,39 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 
 	// Handle array types.
 	if isTypeOrNull(schema, jsonschema.ArrayType) {
-		var elt ast.Expr
+		// Positional item schemas ("prefixItems" or an "items" array) are represented by a Go tuple
+		// type.
+		if g.isTupleType(schema) {
+			return g.namedTypeExpr(schema)
+		}
+		var elt ast.Expr = anyType
 		var imports []*ast.ImportSpec
 		if schema.Items != nil && schema.Items.Schema != nil {
 			var err error
-			elt, imports, err = g.expr(schema.Items.Schema)
+			elt, imports, err = g.arrayElemExpr(schema.Items.Schema)
 			if err != nil {
 				return nil, nil, err
 			}
-			// Prefer array-of-pointer-to-struct over array-of-struct.
-			//
-			// TODO(sqs): Not all $ref values point to things that are Go named types.
-			useGoTaggedUnionType := schema.Items.Schema.Go != nil && schema.Items.Schema.Go.TaggedUnionType
-			if (isEmittedAsGoNamedType(schema.Items.Schema) || schema.Items.Schema.Reference != nil) && !useGoTaggedUnionType {
-				elt = &ast.StarExpr{X: elt}
-			}
-		} else {
-			elt = anyType
 		}
 		return &ast.ArrayType{Elt: elt}, imports, nil
 	}
 
+	// Handle anyOf (or oneOf) schemas that are represented by a Go union type.
+	if g.isUnionType(schema) {
+		return g.namedTypeExpr(schema)
+	}
+
+	// Handle object types whose properties are (at least in part) defined by allOf branches.
+	if g.isAllOfStructType(schema) {
+		return g.namedTypeExpr(schema)
+	}
+
 	// Handle object types with no properties: emit a Go map type instead of a named struct type.
 	// The map's value type comes from additionalProperties. When additionalProperties is omitted it
 	// defaults to true per JSON Schema (any value is allowed), so use any; otherwise emit would not
 	// declare a named type for the object and the generated code would not compile.
 	if isTypeOrNull(schema, jsonschema.ObjectType) && schema.Properties == nil &&
-		(schema.Go == nil || !schema.Go.TaggedUnionType) {
+		!g.isTaggedUnionType(schema) {
 		var valueType ast.Expr = anyType
 		var imports []*ast.ImportSpec
 		if schema.AdditionalProperties != nil {
@@ -182,13 +315,27 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 				return nil, nil, err
 			}
 		}
+		// The values of properties that match patternProperties have the types of those schemas, so
+		// the map's value type is only more specific than any if all of them agree.
+		if schema.PatternProperties != nil && len(*schema.PatternProperties) > 0 {
+			var err error
+			valueType, imports, err = g.patternPropertiesMapValueExpr(schema, valueType, imports)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 		return &ast.MapType{Key: ast.NewIdent("string"), Value: valueType}, imports, nil
 	}
 
+	// Handle enums that are represented by a Go named type.
+	if _, ok := g.enumType(schema); ok {
+		return g.namedTypeExpr(schema)
+	}
+
 	nullable := isNullable(schema)
 	// Handle types represented by Go builtin types or some other non-named types.
 	if (nullable && len(schema.Type) != 2) || (!nullable && len(schema.Type) != 1) {
-		if schema.Go == nil || !schema.Go.TaggedUnionType {
+		if !g.isTaggedUnionType(schema) {
 			return anyType, nil, nil
 		}
 	}
@@ -199,10 +346,22 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 				typ = schema.Type[1]
 			}
 		}
-		if builtin := goBuiltinType(typ); builtin != "" {
+		if builtin := g.goBuiltinType(typ); builtin != "" {
 			if schema.Go != nil && schema.Go.TypeName != "" {
 				return ast.NewIdent(schema.Go.TypeName), nil, nil
 			}
+			if typ == jsonschema.StringType {
+				if expr, imports, ok := g.formatTypeExpr(schema); ok {
+					return expr, imports, nil
+				}
+			}
+			if typ == jsonschema.IntegerType {
+				name, err := g.goIntegerType(schema)
+				if err != nil {
+					return nil, nil, err
+				}
+				return ast.NewIdent(name), nil, nil
+			}
 
 			return ast.NewIdent(builtin), nil, nil
 		}
@@ -215,17 +374,52 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 	}
 
 	// Otherwise, use a Go named type.
-	_, location := g.schemaLocator.locateSchema(schema)
+	return g.namedTypeExpr(schema)
+}
+
+// arrayElemExpr returns the Go expression AST node that refers to the Go type of the elements of a
+// Go slice that holds items described by schema.
+func (g *generator) arrayElemExpr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec, error) {
+	elt, imports, err := g.expr(schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Prefer array-of-pointer-to-struct over array-of-struct.
+	//
+	// TODO(sqs): Not all $ref values point to things that are Go named types.
+	useGoTaggedUnionType := g.isTaggedUnionType(schema)
+	_, useGoEnumType := g.enumType(g.resolve(schema))
+	if (isEmittedAsGoNamedType(schema) || g.isAllOfStructType(schema) || schema.Reference != nil) && !useGoTaggedUnionType && !useGoEnumType {
+		elt = &ast.StarExpr{X: elt}
+	}
+	return elt, imports, nil
+}
+
+// namedTypeExpr returns the Go expression AST node that refers to the Go named type emitted for
+// schema.
+func (g *generator) namedTypeExpr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec, error) {
+	root, location := g.schemaLocator.locateSchema(schema)
 	if location == nil {
 		return nil, nil, errors.New("unable to locate schema")
 	}
-	goName, err := goNameForSchema(schema, *location)
+	goName, err := g.goNameForSchema(schema, *location)
 	if err != nil {
 		return nil, nil, err
 	}
+	if importPath := g.opts.importPathForRoot(root); importPath != "" {
+		expr, imports := qualifiedTypeExpr(importPath, goName)
+		return expr, imports, nil
+	}
 	return ast.NewIdent(goName), nil, nil
 }
 
+// warnf reports a warning about the compilation of a schema (if the caller asked for warnings).
+func (g *generator) warnf(format string, args ...any) {
+	if g.opts.Warn != nil {
+		g.opts.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
 func docForSchema(schema *jsonschema.Schema, goName string) *ast.CommentGroup {
 	if schema.Description == nil {
 		return nil
@@ -0,0 +1,249 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// isAllOfStructType reports whether schema is an object schema that is represented by a Go struct
+// type whose fields include the properties defined by schema's allOf branches.
+//
+// Every branch must be an object schema (or a schema with no type, such as one that only lists
+// required properties), and schema or at least one branch must define properties.
+func (g *generator) isAllOfStructType(schema *jsonschema.Schema) bool {
+	return g.isAllOfStructTypeSeen(schema, map[*jsonschema.Schema]struct{}{})
+}
+
+func (g *generator) isAllOfStructTypeSeen(schema *jsonschema.Schema, seen map[*jsonschema.Schema]struct{}) bool {
+	if len(schema.AllOf) == 0 || g.isTaggedUnionType(schema) {
+		return false
+	}
+	if len(schema.Type) != 0 && !isTypeOrNull(schema, jsonschema.ObjectType) {
+		return false
+	}
+	if _, ok := seen[schema]; ok {
+		return false // cyclic allOf
+	}
+	seen[schema] = struct{}{}
+
+	hasProperties := schema.Properties != nil
+	for _, ref := range schema.AllOf {
+		branch := g.resolve(ref)
+		// A branch whose Go type is not emitted can only be embedded (see collectStructMembers).
+		if (g.isExternal(branch) && (ref.Reference == nil || !g.isEmbeddableStructType(branch))) || branch.IsNegated {
+			return false
+		}
+		if len(branch.Type) != 0 && !isTypeOrNull(branch, jsonschema.ObjectType) {
+			return false
+		}
+		if len(branch.AllOf) > 0 {
+			if !g.isAllOfStructTypeSeen(branch, seen) {
+				return false
+			}
+			hasProperties = true
+		} else if branch.Properties != nil {
+			hasProperties = true
+		}
+	}
+	return hasProperties
+}
+
+// isStructType reports whether schema is represented by a Go struct type (other than a tagged
+// union type).
+func (g *generator) isStructType(schema *jsonschema.Schema) bool {
+	if g.isExternal(schema) {
+		return false
+	}
+	return (isTypeOrNull(schema, jsonschema.ObjectType) && schema.Properties != nil) || g.isAllOfStructType(schema)
+}
+
+// isEmbeddableStructType reports whether the Go type for schema, which an allOf branch refers to,
+// can be embedded in the Go struct type for the allOf. It must be a Go struct type (emitted here or
+// in another package; see Options.ImportPaths) or an existing Go type that represents an object
+// schema with properties (see Options.GoTypes), and it must have no fields for additionalProperties
+// or patternProperties.
+func (g *generator) isEmbeddableStructType(schema *jsonschema.Schema) bool {
+	if hasExtraPropertiesFields(schema) {
+		return false
+	}
+	if g.isExternal(schema) {
+		return isTypeOrNull(schema, jsonschema.ObjectType) && schema.Properties != nil
+	}
+	return g.isStructType(schema)
+}
+
+// markMergedAllOfBranches records the inline (non-$ref) allOf branches of each schema that is
+// represented by a Go struct type. Their properties are merged into the struct type, so no Go type
+// is emitted for them.
+func (g *generator) markMergedAllOfBranches() {
+	g.mergedAllOfBranches = map[*jsonschema.Schema]struct{}{}
+	var mark func(schema *jsonschema.Schema)
+	mark = func(schema *jsonschema.Schema) {
+		for _, branch := range schema.AllOf {
+			if branch.Reference == nil {
+				if _, ok := g.mergedAllOfBranches[branch]; !ok {
+					g.mergedAllOfBranches[branch] = struct{}{}
+					mark(branch)
+				}
+			}
+		}
+	}
+	for schema := range g.schemas {
+		if g.isAllOfStructType(schema) {
+			mark(schema)
+		}
+	}
+}
+
+// structProperty is a property that is represented by a field of a Go struct type.
+type structProperty struct {
+	schemas  []*jsonschema.Schema // the property's schemas (more than one if several allOf branches define it)
+	typeExpr ast.Expr
+	imports  []*ast.ImportSpec
+}
+
+// structEmbed is a Go named struct type that is embedded (as an anonymous field) in a Go struct
+// type because an allOf branch of the latter's schema is a $ref to the former's schema.
+type structEmbed struct {
+	name       string   // the name of the embedded field (the unqualified type name)
+	typeExpr   ast.Expr // T, pkg.T, *T, or *pkg.T
+	imports    []*ast.ImportSpec
+	schema     *jsonschema.Schema         // the schema that the allOf branch refers to
+	properties map[string]*structProperty // the properties that the embedded struct's fields represent
+}
+
+// structMembers returns the properties (and the names of the required properties) that are
+// represented by the fields of the Go struct type for schema, and the Go struct types embedded in
+// it.
+//
+// The properties of inline allOf branches are merged into the struct type's own properties. An
+// allOf branch that is a $ref to a schema represented by a Go struct type is embedded instead
+// (unless either schema allows additionalProperties or has patternProperties, in which case its
+// properties are merged, because the MarshalJSON and UnmarshalJSON methods emitted for them don't
+// support embedded types).
+//
+// It returns an error if two branches define a property with conflicting Go types.
+func (g *generator) structMembers(schema *jsonschema.Schema) (properties map[string]*structProperty, required map[string]struct{}, embeds []*structEmbed, err error) {
+	properties = map[string]*structProperty{}
+	required = map[string]struct{}{}
+	if err := g.collectStructMembers(schema, hasExtraPropertiesFields(schema), properties, required, &embeds); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// A property that is represented by a field of an embedded struct type needs no field of its
+	// own (and two embedded struct types must not both represent it, or else encoding/json would
+	// ignore both fields).
+	for i, embed := range embeds {
+		for _, name := range sortedKeys(embed.properties) {
+			embedded := embed.properties[name]
+			for _, other := range embeds[:i] {
+				if _, ok := other.properties[name]; ok {
+					return nil, nil, nil, fmt.Errorf("property %q is defined by both embedded types %s and %s", name, types.ExprString(other.typeExpr), types.ExprString(embed.typeExpr))
+				}
+			}
+			if prop, ok := properties[name]; ok {
+				if _, err := mergeStructProperty(name, embedded, prop); err != nil {
+					return nil, nil, nil, fmt.Errorf("%w (in embedded type %s)", err, types.ExprString(embed.typeExpr))
+				}
+				delete(properties, name)
+			}
+		}
+	}
+	return properties, required, embeds, nil
+}
+
+func (g *generator) collectStructMembers(schema *jsonschema.Schema, flatten bool, properties map[string]*structProperty, required map[string]struct{}, embeds *[]*structEmbed) error {
+	if schema.Properties != nil {
+		for _, name := range sortedKeys(*schema.Properties) {
+			prop := (*schema.Properties)[name]
+			typeExpr, imports, err := g.expr(prop)
+			if err != nil {
+				return fmt.Errorf("failed to get type expression for property %q: %w", name, err)
+			}
+			p := &structProperty{schemas: []*jsonschema.Schema{prop}, typeExpr: typeExpr, imports: imports}
+			if other, ok := properties[name]; ok {
+				if p, err = mergeStructProperty(name, other, p); err != nil {
+					return err
+				}
+			}
+			properties[name] = p
+		}
+	}
+	for _, name := range schema.Required {
+		required[name] = struct{}{}
+	}
+
+	for i, branch := range schema.AllOf {
+		if branch.Reference != nil {
+			target := g.resolve(branch)
+			if !flatten && g.isEmbeddableStructType(target) {
+				typeExpr, imports, err := g.expr(branch)
+				if err != nil {
+					return fmt.Errorf("failed to get type expression for allOf branch %d: %w", i, err)
+				}
+				name, ok := embeddedFieldName(typeExpr)
+				if !ok {
+					return fmt.Errorf("allOf branch %d refers to a schema whose Go type %s can't be embedded", i, types.ExprString(typeExpr))
+				}
+				embeddedProperties, _, _, err := g.structMembers(target)
+				if err != nil {
+					return fmt.Errorf("allOf branch %d: %w", i, err)
+				}
+				*embeds = append(*embeds, &structEmbed{name: name, typeExpr: typeExpr, imports: imports, schema: target, properties: embeddedProperties})
+				continue
+			}
+			if g.isExternal(target) {
+				return fmt.Errorf("allOf branch %d refers to a schema whose Go type is not emitted, so its properties can't be merged (because the schema allows additionalProperties or has patternProperties)", i)
+			}
+			branch = target
+		}
+		if err := g.collectStructMembers(branch, flatten, properties, required, embeds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embeddedFieldName returns the name of the embedded field of a Go struct type whose type is
+// typeExpr, if it is a (possibly qualified) Go type name or a pointer to one.
+func embeddedFieldName(typeExpr ast.Expr) (string, bool) {
+	if star, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = star.X
+	}
+	switch t := typeExpr.(type) {
+	case *ast.Ident:
+		return t.Name, true
+	case *ast.SelectorExpr:
+		if _, ok := t.X.(*ast.Ident); ok {
+			return t.Sel.Name, true
+		}
+	}
+	return "", false
+}
+
+// mergeStructProperty returns the property that represents both a and b, which are definitions of
+// the property with the given name in different allOf branches. If their Go types are the same, it
+// has the schemas of both (so that the values are validated against, and given the defaults of,
+// both). Otherwise the Go type of one must be any, and the other is returned. It returns an error if
+// their Go types conflict.
+func mergeStructProperty(name string, a, b *structProperty) (*structProperty, error) {
+	aType, bType := types.ExprString(a.typeExpr), types.ExprString(b.typeExpr)
+	switch {
+	case aType == bType:
+		schemas := append(append([]*jsonschema.Schema{}, a.schemas...), b.schemas...)
+		return &structProperty{schemas: schemas, typeExpr: a.typeExpr, imports: a.imports}, nil
+	case bType == anyType.Name:
+		return a, nil
+	case aType == anyType.Name:
+		return b, nil
+	}
+	return nil, fmt.Errorf("allOf branches define property %q with conflicting Go types %s and %s", name, aType, bType)
+}
+
+// allowsAdditionalProperties reports whether the Go struct type for schema has an Additional field.
+func allowsAdditionalProperties(schema *jsonschema.Schema) bool {
+	return schema.AdditionalProperties != nil && !schema.AdditionalProperties.IsNegated
+}
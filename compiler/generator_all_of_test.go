@@ -0,0 +1,66 @@
+package compiler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestCompile_allOfConflicts(t *testing.T) {
+	tests := map[string]struct {
+		schema  string
+		wantErr string
+	}{
+		"conflicting property types": {
+			schema: `{
+  "title": "t",
+  "allOf": [
+    {"properties": {"a": {"type": "string"}}},
+    {"properties": {"a": {"type": "integer"}}}
+  ]
+}`,
+			wantErr: `allOf branches define property "a" with conflicting Go types string and int`,
+		},
+		"conflict with embedded type": {
+			schema: `{
+  "title": "t",
+  "allOf": [
+    {"$ref": "#/definitions/base"},
+    {"properties": {"a": {"type": "boolean"}}}
+  ],
+  "definitions": {
+    "base": {"type": "object", "properties": {"a": {"type": "string"}}}
+  }
+}`,
+			wantErr: `allOf branches define property "a" with conflicting Go types string and bool (in embedded type Base)`,
+		},
+		"property of multiple embedded types": {
+			schema: `{
+  "title": "t",
+  "allOf": [
+    {"$ref": "#/definitions/x"},
+    {"$ref": "#/definitions/y"}
+  ],
+  "definitions": {
+    "x": {"type": "object", "properties": {"a": {"type": "string"}}},
+    "y": {"type": "object", "properties": {"a": {"type": "string"}}}
+  }
+}`,
+			wantErr: `property "a" is defined by both embedded types X and Y`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema jsonschema.Schema
+			if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := Compile([]*jsonschema.Schema{&schema})
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("got error %v, want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package compiler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// hasApplyDefaultsMethod reports whether the Go type for schema has an ApplyDefaults method. A Go
+// struct type with a field named ApplyDefaults has none.
+func (g *generator) hasApplyDefaultsMethod(schema *jsonschema.Schema) bool {
+	if !g.opts.EmitApplyDefaultsMethods {
+		return false
+	}
+	schema = g.resolve(schema)
+	if g.isExternal(schema) || !g.isStructType(schema) {
+		return false
+	}
+	_, conflicts := g.propertyForField(schema, "ApplyDefaults")
+	return !conflicts
+}
+
+// hasAppliedDefault reports whether the ApplyDefaults method sets the field for the property with
+// the given schemas to a default value, which requires the field to be a pointer (or another
+// nilable type) if the property is optional.
+func (g *generator) hasAppliedDefault(schemas ...*jsonschema.Schema) bool {
+	if !g.opts.EmitApplyDefaultsMethods {
+		return false
+	}
+	_, _, ok := g.propertyDefault(schemas)
+	return ok
+}
+
+// propertyDefault returns the default value of the property with the given schemas (one for each
+// allOf branch that defines it): that of the first schema that has one (on itself or on the schema
+// that it refers to), and that schema.
+func (g *generator) propertyDefault(schemas []*jsonschema.Schema) (*jsonschema.Schema, any, bool) {
+	for _, schema := range schemas {
+		if value, ok := schemaDefault(g.resolve(schema), schema); ok {
+			return schema, value, true
+		}
+	}
+	return nil, nil, false
+}
+
+// errUnsupportedDefault is returned (wrapped) by defaultLiteral for a default value that is valid
+// but can't be represented by a Go literal.
+var errUnsupportedDefault = errors.New("unsupported default value")
+
+// emitApplyDefaultsMethod returns an ApplyDefaults method for the Go struct type (named goName)
+// for schema. The method sets each field whose property has a "default" (on its schema or on the
+// schema that it refers to) and whose value is absent (nil, or the zero value of a non-pointer
+// type) to the default value, and then calls the ApplyDefaults method of each of its values (and of
+// their elements) that has one.
+//
+// A default value that is not valid for the Go type of its field is an error. A default value that
+// is valid but can't be represented by a Go literal (such as one for a Go union type) is ignored
+// and reported by Options.Warn.
+func (g *generator) emitApplyDefaultsMethod(schema *jsonschema.Schema, goName string, fields []field) (*ast.FuncDecl, error) {
+	c := validateCode{g: g, goName: goName, imports: map[string]struct{}{}}
+	for _, f := range fields {
+		x := "v." + f.GoName
+		if f.embedded {
+			if g.hasApplyDefaultsMethod(f.schema) {
+				if isNilable(f.Type) {
+					c.printf("if %s != nil {\n%s.ApplyDefaults()\n}\n", x, x)
+				} else {
+					c.printf("%s.ApplyDefaults()\n", x)
+				}
+			}
+			continue
+		}
+
+		if prop, value, ok := g.propertyDefault(f.schemas); ok {
+			if err := c.applyDefault(prop, f.Type, x, value); errors.Is(err, errUnsupportedDefault) {
+				g.warnf("default for property %q at %q is ignored (%s)", f.JSONName, jsonschema.EncodeReferenceTokens(g.schemas[schema].rel), err)
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid default for property %q: %w", f.JSONName, err)
+			}
+		}
+		if err := c.applyNestedDefaults(f.schema, f.Type, x); err != nil {
+			return nil, err
+		}
+	}
+
+	decl, err := parseFuncLitToFuncDecl("func() {\n" + c.buf.String() + "}")
+	if err != nil {
+		return nil, err
+	}
+	makeMethod(decl, &ast.StarExpr{X: ast.NewIdent(goName)}, "ApplyDefaults")
+	return decl, nil
+}
+
+// schemaDefault returns the "default" of the first of the schemas that has one.
+func schemaDefault(schemas ...*jsonschema.Schema) (any, bool) {
+	for _, schema := range schemas {
+		if schema.Default != nil {
+			return *schema.Default, true
+		}
+	}
+	return nil, false
+}
+
+// applyDefault emits code to set x (whose type is typ) to the default value if x is absent.
+func (c *validateCode) applyDefault(schema *jsonschema.Schema, typ ast.Expr, x string, value any) error {
+	if c.g.isExternal(c.g.resolve(schema)) {
+		return fmt.Errorf("%w: Go type %s is not generated from the schema", errUnsupportedDefault, types.ExprString(typ))
+	}
+	if star, ok := typ.(*ast.StarExpr); ok && value != nil && !c.g.isStructType(c.g.resolve(schema)) {
+		// Assign the address of a variable that holds the default value.
+		lit, err := c.g.defaultLiteral(schema, star.X, value)
+		if err != nil {
+			return err
+		}
+		if typeName := types.ExprString(star.X); !strings.HasPrefix(lit, typeName+"(") {
+			lit = typeName + "(" + lit + ")"
+		}
+		d := c.newVar("d")
+		c.printf("if %s == nil {\n%s := %s\n%s = &%s\n}\n", x, d, lit, x, d)
+		return nil
+	}
+
+	lit, err := c.g.defaultLiteral(schema, typ, value)
+	if err != nil {
+		return err
+	}
+	if !isNilable(typ) {
+		// Comparing x to the zero value would overwrite a zero value that was set explicitly.
+		return fmt.Errorf("%w: the zero value of Go type %s can't be distinguished from an absent value", errUnsupportedDefault, types.ExprString(typ))
+	}
+	c.printf("if %s == nil {\n%s = %s\n}\n", x, x, lit)
+	return nil
+}
+
+// applyNestedDefaults emits code to call the ApplyDefaults method of x (whose type is typ), or of
+// its elements, if they have one.
+func (c *validateCode) applyNestedDefaults(schema *jsonschema.Schema, typ ast.Expr, x string) error {
+	schema = c.g.resolve(schema)
+	if c.g.isExternal(schema) {
+		return nil
+	}
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		if c.g.hasApplyDefaultsMethod(schema) {
+			c.printf("if %s != nil {\n%s.ApplyDefaults()\n}\n", x, x)
+		}
+	case *ast.Ident:
+		if c.g.hasApplyDefaultsMethod(schema) {
+			c.printf("%s.ApplyDefaults()\n", x)
+		}
+	case *ast.ArrayType:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return nil
+		}
+		i := c.newVar("i")
+		return c.block(fmt.Sprintf("for %s := range %s", i, x), func() error {
+			return c.applyNestedDefaults(schema.Items.Schema, t.Elt, fmt.Sprintf("%s[%s]", x, i))
+		})
+	case *ast.MapType:
+		if schema.AdditionalProperties == nil || !c.g.hasApplyDefaultsMethod(schema.AdditionalProperties) {
+			return nil
+		}
+		k, e := c.newVar("k"), c.newVar("e")
+		if _, ok := t.Value.(*ast.StarExpr); ok {
+			c.printf("for _, %s := range %s {\nif %s != nil {\n%s.ApplyDefaults()\n}\n}\n", e, x, e, e)
+		} else {
+			// Map elements aren't addressable, so apply the defaults to a copy and store it.
+			c.printf("for %s, %s := range %s {\n%s.ApplyDefaults()\n%s[%s] = %s\n}\n", k, e, x, e, x, k, e)
+		}
+	}
+	return nil
+}
+
+// defaultLiteral returns the Go literal (of the Go type typ) for the default value of schema. It
+// returns an error if the value is not valid for typ.
+func (g *generator) defaultLiteral(schema *jsonschema.Schema, typ ast.Expr, value any) (string, error) {
+	schema = g.resolve(schema)
+	typeName := types.ExprString(typ)
+	invalid := func() error {
+		data, _ := json.Marshal(value)
+		return fmt.Errorf("default value %s is not a valid value of Go type %s", data, typeName)
+	}
+	if value == nil {
+		if isNilable(typ) {
+			return "nil", nil
+		}
+		return "", invalid()
+	}
+
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		lit, err := g.defaultLiteral(schema, t.X, value)
+		if err != nil {
+			return "", err
+		}
+		if g.isStructType(schema) {
+			return "&" + lit, nil
+		}
+		return fmt.Sprintf("func() %s { v := %s(%s); return &v }()", typeName, types.ExprString(t.X), lit), nil
+
+	case *ast.ArrayType:
+		items, ok := value.([]any)
+		if !ok {
+			return "", invalid()
+		}
+		itemSchema := &jsonschema.Schema{IsEmpty: true}
+		if schema.Items != nil && schema.Items.Schema != nil {
+			itemSchema = schema.Items.Schema
+		}
+		lits := make([]string, len(items))
+		for i, item := range items {
+			var err error
+			if lits[i], err = g.defaultLiteral(itemSchema, t.Elt, item); err != nil {
+				return "", fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		return typeName + "{" + strings.Join(lits, ", ") + "}", nil
+
+	case *ast.MapType:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return "", invalid()
+		}
+		valueSchema := &jsonschema.Schema{IsEmpty: true}
+		if schema.AdditionalProperties != nil {
+			valueSchema = schema.AdditionalProperties
+		}
+		lits := make([]string, 0, len(object))
+		for _, k := range sortedKeys(object) {
+			lit, err := g.defaultLiteral(valueSchema, t.Value, object[k])
+			if err != nil {
+				return "", fmt.Errorf("property %q: %w", k, err)
+			}
+			lits = append(lits, strconv.Quote(k)+": "+lit)
+		}
+		return typeName + "{" + strings.Join(lits, ", ") + "}", nil
+
+	case *ast.Ident:
+		switch {
+		case t.Name == anyType.Name:
+			return anyLiteral(value), nil
+		case t.Name == "bool":
+			if lit, ok := goLiteral(value, jsonschema.BooleanType); ok {
+				return lit, nil
+			}
+		case t.Name == "string":
+			if lit, ok := goLiteral(value, jsonschema.StringType); ok {
+				return lit, nil
+			}
+		case t.Name == "float32" || t.Name == "float64":
+			if f, ok := value.(float64); ok && (t.Name == "float64" || math.Abs(f) <= math.MaxFloat32) {
+				return formatFloat(f), nil
+			}
+		case isBasicType(t): // an integer type
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				for _, it := range goIntegerTypes {
+					if it.name == t.Name && f >= it.min && f <= it.max {
+						return strconv.FormatFloat(f, 'f', -1, 64), nil
+					}
+				}
+			}
+		default:
+			if typ, ok := g.enumType(schema); ok {
+				lit, ok := goLiteral(value, typ)
+				if !ok || !enumContains(schema.Enum, value) {
+					return "", invalid()
+				}
+				return fmt.Sprintf("%s(%s)", t.Name, lit), nil
+			}
+			if g.isStructType(schema) {
+				return g.structDefaultLiteral(schema, t.Name, value)
+			}
+			return "", fmt.Errorf("%w: Go type %s", errUnsupportedDefault, typeName)
+		}
+		return "", invalid()
+	}
+	return "", fmt.Errorf("%w: Go type %s", errUnsupportedDefault, typeName)
+}
+
+// structDefaultLiteral returns the Go composite literal (of the Go struct type named goName) for
+// the default value of the object schema.
+func (g *generator) structDefaultLiteral(schema *jsonschema.Schema, goName string, value any) (string, error) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		data, _ := json.Marshal(value)
+		return "", fmt.Errorf("default value %s is not a valid value of Go type %s", data, goName)
+	}
+	fields, _, err := g.structFields(schema)
+	if err != nil {
+		return "", err
+	}
+	fieldsByJSONName := make(map[string]field, len(fields))
+	for _, f := range fields {
+		if f.embedded {
+			return "", fmt.Errorf("%w: Go type %s has embedded types", errUnsupportedDefault, goName)
+		}
+		fieldsByJSONName[f.JSONName] = f
+	}
+
+	lits := make([]string, 0, len(object))
+	for _, k := range sortedKeys(object) {
+		f, ok := fieldsByJSONName[k]
+		if !ok {
+			if hasExtraPropertiesFields(schema) {
+				return "", fmt.Errorf("%w: property %q is not a field of Go type %s", errUnsupportedDefault, k, goName)
+			}
+			return "", fmt.Errorf("default value has property %q, which is not allowed by Go type %s", k, goName)
+		}
+		lit, err := g.defaultLiteral(f.schema, f.Type, object[k])
+		if err != nil {
+			return "", fmt.Errorf("property %q: %w", k, err)
+		}
+		lits = append(lits, f.GoName+": "+lit)
+	}
+	return goName + "{" + strings.Join(lits, ", ") + "}", nil
+}
+
+// anyLiteral returns the Go literal (of type any) for the JSON value, as decoded by encoding/json.
+func anyLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return "float64(" + formatFloat(v) + ")"
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		lits := make([]string, len(v))
+		for i, item := range v {
+			lits[i] = anyLiteral(item)
+		}
+		return "[]any{" + strings.Join(lits, ", ") + "}"
+	case map[string]any:
+		lits := make([]string, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			lits = append(lits, strconv.Quote(k)+": "+anyLiteral(v[k]))
+		}
+		return "map[string]any{" + strings.Join(lits, ", ") + "}"
+	}
+	panic(fmt.Sprintf("unexpected JSON value of type %T", value))
+}
+
+// enumContains reports whether the enum values include value.
+func enumContains(enum jsonschema.EnumList, value any) bool {
+	for _, v := range enum {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
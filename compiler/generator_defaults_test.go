@@ -0,0 +1,129 @@
+package compiler
+
+import (
+	"encoding/json"
+	"go/ast"
+	"reflect"
+	"testing"
+
+	testdata_defaults "github.com/sourcegraph/go-jsonschema/compiler/testdata/defaults"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestEmitApplyDefaultsMethods_invalidDefaults(t *testing.T) {
+	tests := map[string]struct {
+		property string
+		want     string
+	}{
+		"string for integer": {
+			property: `{"type": "integer", "default": "1"}`,
+			want:     `invalid default for property "p": default value "1" is not a valid value of Go type int`,
+		},
+		"fractional integer": {
+			property: `{"type": "integer", "default": 1.5}`,
+			want:     `invalid default for property "p": default value 1.5 is not a valid value of Go type int`,
+		},
+		"array item": {
+			property: `{"type": "array", "items": {"type": "boolean"}, "default": [true, 0]}`,
+			want:     `invalid default for property "p": item 1: default value 0 is not a valid value of Go type bool`,
+		},
+		"unknown struct property": {
+			property: `{"type": "object", "properties": {"a": {"type": "string"}}, "default": {"b": "x"}}`,
+			want:     `invalid default for property "p": default value has property "b", which is not allowed by Go type P`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema jsonschema.Schema
+			if err := json.Unmarshal([]byte(`{"title": "t", "type": "object", "properties": {"p": `+test.property+`}}`), &schema); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{EmitApplyDefaultsMethods: true})
+			if err == nil {
+				t.Fatal("got nil error, want error")
+			}
+			if want := "generating decls: failed to emit decl for schema: failed to emit ApplyDefaults method for T: " + test.want; err.Error() != want {
+				t.Errorf("got error %q, want %q", err, want)
+			}
+		})
+	}
+}
+
+func TestEmitApplyDefaultsMethods_unsupportedDefault(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{
+  "title": "t",
+  "type": "object",
+  "required": ["p", "q"],
+  "properties": {
+    "p": {"type": "object", "properties": {"a": {"type": "string"}}, "default": {"a": "x"}},
+    "q": {"type": "integer", "default": 1}
+  }
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	if _, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+		EmitApplyDefaultsMethods: true,
+		Warn:                     func(message string) { warnings = append(warnings, message) },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`default for property "p" at "" is ignored (unsupported default value: the zero value of Go type P can't be distinguished from an absent value)`,
+		`default for property "q" at "" is ignored (unsupported default value: the zero value of Go type int can't be distinguished from an absent value)`,
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+}
+
+func TestEmitApplyDefaultsMethods_methodConflict(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{
+  "title": "t",
+  "type": "object",
+  "properties": {
+    "applyDefaults": {"type": "boolean"},
+    "p": {"type": "string", "default": "x"}
+  }
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	decls, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+		EmitApplyDefaultsMethods: true,
+		Warn:                     func(message string) { warnings = append(warnings, message) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`T has a property "applyDefaults", which conflicts with the ApplyDefaults method, so no ApplyDefaults method will be emitted for it`}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+	for _, decl := range decls {
+		if d, ok := decl.(*ast.FuncDecl); ok && d.Name.Name == "ApplyDefaults" {
+			t.Errorf("got an ApplyDefaults method")
+		}
+	}
+}
+
+// TestApplyDefaultsMethods depends on the generated ./testdata/defaults/want.go file, which you can
+// overwrite with the latest generated code by running `go test -test.write-want`.
+func TestApplyDefaultsMethods(t *testing.T) {
+	var v testdata_defaults.Defaults
+	if err := json.Unmarshal([]byte(`{"client": {}, "enabled": false, "port": 0, "server": {"host": "h", "timeout": 0}}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	v.ApplyDefaults()
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Explicit zero values are kept, and only absent values are set to their defaults.
+	want := `{"client":{"retries":3},"enabled":false,"extra":{"k":[1,"two",null]},"labels":{"x":1},"mode":"dev","name":"anonymous","port":0,"ratio":0.5,"server":{"host":"h","timeout":0},"tags":["a","b"]}`
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
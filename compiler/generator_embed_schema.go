@@ -0,0 +1,124 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// emitEmbeddedSchema returns a constant that holds the canonical JSON encoding of the root schema
+// (named <type>SchemaJSON) and a Schema method on the root schema's Go type that returns the
+// schema (parsed from the constant once, by a function in a variable named parsed<type>Schema).
+// If the root schema has no Go named type (or the type has a Schema field), it returns no
+// declarations and reports a warning.
+func (g *generator) emitEmbeddedSchema(root *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
+	if !g.emitsNamedType(root) {
+		g.warnf("root schema %s has no Go named type, so it will not be embedded", describeRoot(root))
+		return nil, nil, nil
+	}
+	goName, err := g.goNameForSchema(root, g.schemas[root])
+	if err != nil {
+		return nil, nil, err
+	}
+	if g.isStructType(root) {
+		if name, ok := g.propertyForField(root, "Schema"); ok {
+			g.warnf("root schema %s has a property %q, which conflicts with the Schema method, so it will not be embedded", describeRoot(root), name)
+			return nil, nil, nil
+		}
+	}
+
+	data, err := canonicalSchemaJSON(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode root schema %s: %w", describeRoot(root), err)
+	}
+	value := "`" + string(data) + "`"
+	if bytes.ContainsRune(data, '`') {
+		value = strconv.Quote(string(data))
+	}
+	constName := goName + "SchemaJSON"
+	constDecl := &ast.GenDecl{
+		Doc: &ast.CommentGroup{List: []*ast.Comment{{
+			Text: fmt.Sprintf("\n// %s is the JSON Schema that %s was generated from.", constName, goName),
+		}}},
+		Tok: token.CONST,
+		Specs: []ast.Spec{&ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent(constName)},
+			Values: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: value}},
+		}},
+	}
+
+	// Parse the constant only once, when the Schema method is first called.
+	varName := "parsed" + goName + "Schema"
+	parseFunc, err := parser.ParseExpr(executeTemplate(embeddedSchemaParseTemplate, map[string]any{"constName": constName}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing embedded schema parse func: %w", err)
+	}
+	varDecl := &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{&ast.ValueSpec{
+			Names: []*ast.Ident{ast.NewIdent(varName)},
+			Values: []ast.Expr{&ast.CallExpr{
+				Fun:  &ast.SelectorExpr{X: ast.NewIdent("sync"), Sel: ast.NewIdent("OnceValue")},
+				Args: []ast.Expr{parseFunc},
+			}},
+		}},
+	}
+
+	methodDecl, err := parseFuncLitToFuncDecl("func() *jsonschema.Schema {\nreturn " + varName + "()\n}")
+	if err != nil {
+		return nil, nil, err
+	}
+	makeMethod(methodDecl, ast.NewIdent(goName), "Schema")
+	return []ast.Decl{constDecl, varDecl, methodDecl}, importSpecs("encoding/json", "sync", "github.com/sourcegraph/go-jsonschema/jsonschema"), nil
+}
+
+// canonicalSchemaJSON returns the JSON encoding of the root schema (from the document it was
+// unmarshaled from, if any, so that extension keywords are preserved), with object keys sorted
+// and indented. Numbers are encoded as they appear in the document (not rounded to a float64).
+func canonicalSchemaJSON(root *jsonschema.Schema) ([]byte, error) {
+	var data []byte
+	if root.Raw != nil {
+		data = *root.Raw
+	} else {
+		var err error
+		if data, err = json.Marshal(root); err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// describeRoot returns a description of the root schema for use in messages.
+func describeRoot(root *jsonschema.Schema) string {
+	switch {
+	case root.ID != nil:
+		return strconv.Quote(strings.TrimSuffix(*root.ID, "#"))
+	case root.Title != nil:
+		return strconv.Quote(*root.Title)
+	}
+	return "(untitled)"
+}
+
+var embeddedSchemaParseTemplate = template.Must(template.New("").Parse(`
+func() *jsonschema.Schema {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte({{.constName}}), &schema); err != nil {
+		panic("invalid embedded JSON Schema: " + err.Error())
+	}
+	return &schema
+}
+`))
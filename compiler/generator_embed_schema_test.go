@@ -0,0 +1,68 @@
+package compiler
+
+import (
+	"encoding/json"
+	"go/ast"
+	"reflect"
+	"testing"
+
+	embedschema "github.com/sourcegraph/go-jsonschema/compiler/testdata/embed-schema"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestEmbedSchemas(t *testing.T) {
+	schema := embedschema.EmbedSchema{}.Schema()
+	if schema.ID == nil || *schema.ID != "https://example.com/embed-schema" {
+		t.Errorf("got $id %v, want %q", schema.ID, "https://example.com/embed-schema")
+	}
+	if again := (embedschema.EmbedSchema{}).Schema(); again != schema {
+		t.Error("got a different *jsonschema.Schema from the second call, want the schema to be parsed once")
+	}
+}
+
+func TestEmbedSchemas_notEmbedded(t *testing.T) {
+	tests := map[string]struct {
+		schema       string
+		retrievalURI string
+		want         string
+	}{
+		"no named type": {
+			schema: `{"title": "t", "type": "string", "description": "d"}`,
+			want:   `root schema "t" has no Go named type, so it will not be embedded`,
+		},
+		"loaded without $id": {
+			schema:       `{"title": "t", "type": "string", "description": "d"}`,
+			retrievalURI: "file:///home/user/t.json",
+			want:         `root schema "t" has no Go named type, so it will not be embedded`,
+		},
+		"Schema field": {
+			schema: `{"title": "t", "type": "object", "properties": {"schema": {"type": "string"}}}`,
+			want:   `root schema "t" has a property "schema", which conflicts with the Schema method, so it will not be embedded`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema jsonschema.Schema
+			if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			schema.RetrievalURI = test.retrievalURI
+			var warnings []string
+			decls, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+				EmbedSchemas: true,
+				Warn:         func(message string) { warnings = append(warnings, message) },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{test.want}; !reflect.DeepEqual(warnings, want) {
+				t.Errorf("got warnings %q, want %q", warnings, want)
+			}
+			for _, decl := range decls {
+				if d, ok := decl.(*ast.FuncDecl); ok && d.Name.Name == "Schema" {
+					t.Error("got Schema method, want none")
+				}
+			}
+		})
+	}
+}
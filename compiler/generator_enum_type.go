@@ -0,0 +1,203 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// enumType returns the primitive type of the values of schema's enum if a named Go type (with a
+// constant for each value) should be emitted for schema. Otherwise it returns false.
+//
+// Only string and integer enums (and number enums whose values are all integers) are emitted as
+// named Go types. The "null" value is allowed in (and ignored for) the enum of a nullable schema.
+func (g *generator) enumType(schema *jsonschema.Schema) (jsonschema.PrimitiveType, bool) {
+	if !g.opts.EmitEnumTypes || len(schema.Enum) == 0 || g.isExternal(schema) || (schema.Go != nil && schema.Go.TypeName != "") || g.isTaggedUnionType(schema) {
+		return "", false
+	}
+
+	typ := jsonSchemaType(schema)
+	if (isNullable(schema) && len(schema.Type) > 2) || (!isNullable(schema) && len(schema.Type) > 1) {
+		return "", false
+	}
+	for _, v := range schema.Enum {
+		var vtyp jsonschema.PrimitiveType
+		switch v := v.(type) {
+		case nil:
+			continue
+		case string:
+			vtyp = jsonschema.StringType
+		case float64:
+			vtyp = jsonschema.NumberType
+			if v == float64(int64(v)) {
+				vtyp = jsonschema.IntegerType
+			}
+		default:
+			return "", false
+		}
+		switch {
+		case typ == jsonschema.UnspecifiedType:
+			typ = vtyp
+		case typ == jsonschema.NumberType && vtyp == jsonschema.IntegerType:
+			// Integer values are allowed in a number enum.
+		case typ != vtyp:
+			return "", false
+		}
+	}
+	if typ != jsonschema.StringType && typ != jsonschema.IntegerType && typ != jsonschema.NumberType {
+		return "", false
+	}
+	for _, v := range schema.Enum {
+		if f, ok := v.(float64); ok && f != float64(int64(f)) {
+			return "", false // there is no good Go constant name for non-integer values
+		}
+	}
+	return typ, true
+}
+
+// emitEnumType emits a named Go type (with a constant for each value) for schema's enum.
+func (g *generator) emitEnumType(schema *jsonschema.Schema, typ jsonschema.PrimitiveType) ([]ast.Decl, []*ast.ImportSpec, error) {
+	goName, err := g.goNameForSchema(schema, g.schemas[schema])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create a constant for each value (ignoring null and duplicate values). If the names of the
+	// constants for distinct values would be the same (as for "+" and "-"), the constants are named
+	// by their index instead.
+	var constNames, literals []string
+	literalsByConstName := map[string]string{}
+	seen := map[string]struct{}{}
+	sameNames := false
+	for _, v := range schema.Enum {
+		if v == nil {
+			continue
+		}
+		lit, ok := goLiteral(v, typ)
+		if !ok {
+			panic(fmt.Sprintf("unexpected enum value %v for type %s", v, typ))
+		}
+		if _, ok := seen[lit]; ok {
+			continue
+		}
+		seen[lit] = struct{}{}
+		constName := goEnumConstName(goName, v)
+		if other, ok := literalsByConstName[constName]; ok && !sameNames {
+			g.warnf("enum at %q has values %s and %s that both have the Go constant name %s, so its constants are named by their index", jsonschema.EncodeReferenceTokens(g.schemas[schema].rel), other, lit, constName)
+			sameNames = true
+		}
+		literalsByConstName[constName] = lit
+		constNames = append(constNames, constName)
+		literals = append(literals, lit)
+	}
+	constDecl := &ast.GenDecl{Tok: token.CONST, Lparen: 1, Rparen: 1}
+	for i, lit := range literals {
+		if sameNames {
+			constNames[i] = goName + "_" + strconv.Itoa(i)
+		}
+		constDecl.Specs = append(constDecl.Specs, &ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent(constNames[i])},
+			Type:   ast.NewIdent(goName),
+			Values: []ast.Expr{&ast.BasicLit{Kind: goLiteralKind(typ), Value: lit}},
+		})
+	}
+
+	decls := []ast.Decl{
+		&ast.GenDecl{
+			Doc: docForSchema(schema, goName),
+			Tok: token.TYPE,
+			Specs: []ast.Spec{&ast.TypeSpec{
+				Name: ast.NewIdent(goName),
+				Type: ast.NewIdent(g.goBuiltinType(typ)),
+			}},
+		},
+		constDecl,
+	}
+	var imports []*ast.ImportSpec
+
+	templateData := map[string]any{
+		"goName":     goName,
+		"goType":     g.goBuiltinType(typ),
+		"constNames": strings.Join(constNames, ", "),
+		"literals":   strings.Join(literals, ", "),
+		"verb":       map[jsonschema.PrimitiveType]string{jsonschema.StringType: "%q", jsonschema.IntegerType: "%d", jsonschema.NumberType: "%v"}[typ],
+	}
+	if g.opts.StrictEnumUnmarshaling {
+		unmarshalJSONDecl, err := parseFuncLitToFuncDecl(executeTemplate(enumTypeUnmarshalJSONTemplate, templateData))
+		if err != nil {
+			return nil, nil, err
+		}
+		makeMethod(unmarshalJSONDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "UnmarshalJSON")
+		decls = append(decls, unmarshalJSONDecl)
+		imports = append(imports, importSpecs("encoding/json", "fmt")...)
+	}
+	if g.opts.EmitValidateMethods {
+		validateDecl, err := parseFuncLitToFuncDecl(executeTemplate(enumTypeValidateTemplate, templateData))
+		if err != nil {
+			return nil, nil, err
+		}
+		makeMethod(validateDecl, ast.NewIdent(goName), "Validate")
+		decls = append(decls, validateDecl)
+		imports = append(imports, importSpecs("fmt")...)
+	}
+	return decls, imports, nil
+}
+
+// goEnumConstName returns the name of the Go constant for an enum value of the named Go type.
+func goEnumConstName(goName string, value any) string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return goName + "Empty"
+		}
+		return goName + toGoName(v, "_")
+	case float64:
+		if v < 0 {
+			return goName + "Minus" + strconv.FormatInt(-int64(v), 10)
+		}
+		return goName + strconv.FormatInt(int64(v), 10)
+	default:
+		panic(fmt.Sprintf("unexpected enum value of type %T", value))
+	}
+}
+
+func goLiteralKind(typ jsonschema.PrimitiveType) token.Token {
+	if typ == jsonschema.StringType {
+		return token.STRING
+	}
+	return token.INT
+}
+
+var (
+	enumTypeUnmarshalJSONTemplate = template.Must(template.New("").Parse(`
+func(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var x {{.goType}}
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	switch {{.goName}}(x) {
+	case {{.constNames}}:
+		*v = {{.goName}}(x)
+		return nil
+	}
+	return fmt.Errorf("invalid {{.goName}} value {{.verb}} (must be one of %s)", x, {{printf "%q" .literals}})
+}
+`))
+	enumTypeValidateTemplate = template.Must(template.New("").Parse(`
+func() error {
+	switch v {
+	case {{.constNames}}:
+		return nil
+	}
+	return fmt.Errorf("invalid {{.goName}} value {{.verb}} (must be one of %s)", {{.goType}}(v), {{printf "%q" .literals}})
+}
+`))
+)
@@ -0,0 +1,61 @@
+package compiler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	testdata_enum "github.com/sourcegraph/go-jsonschema/compiler/testdata/enum"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// TestEnumType depends on the generated ./testdata/enum/want.go file, which you can overwrite with
+// the latest generated code by running `go test -test.write-want`.
+func TestEnumType(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		var got testdata_enum.Enum
+		if err := json.Unmarshal([]byte(`{"protocol":"https","level":-1,"color":null,"colors":["red","green"]}`), &got); err != nil {
+			t.Fatal(err)
+		}
+		want := testdata_enum.Enum{
+			Protocol: testdata_enum.ProtocolHttps,
+			Level:    testdata_enum.LevelMinus1,
+			Colors:   []testdata_enum.Color{testdata_enum.ColorRed, testdata_enum.ColorGreen},
+		}
+		if got.Protocol != want.Protocol || got.Level != want.Level || got.Color != want.Color || len(got.Colors) != 2 || got.Colors[1] != want.Colors[1] {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	tests := map[string]string{
+		"string": `{"protocol":"ftp","level":0}`,
+		"int":    `{"level":3}`,
+		"array":  `{"level":0,"colors":["red","blue"]}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			var v testdata_enum.Enum
+			if err := json.Unmarshal([]byte(data), &v); err == nil {
+				t.Errorf("got nil error, want error for value not in enum")
+			}
+		})
+	}
+}
+
+func TestEmitEnumTypes_sameConstNames(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{"title": "op", "type": "string", "enum": ["+", "-", "+"]}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	if _, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+		EmitEnumTypes: true,
+		Warn:          func(message string) { warnings = append(warnings, message) },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`enum at "" has values "+" and "-" that both have the Go constant name Op_, so its constants are named by their index`}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+}
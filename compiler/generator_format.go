@@ -0,0 +1,86 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// formatPackage is the import path of the package that provides Go types for string formats that
+// have no suitable type in the Go standard library.
+const formatPackage = "github.com/sourcegraph/go-jsonschema/format"
+
+// formatGoTypes maps each supported "format" keyword value to the Go type (import path and type
+// name) that represents strings of that format.
+var formatGoTypes = map[jsonschema.Format]struct{ importPath, name string }{
+	"date-time":     {"time", "Time"},
+	"duration":      {formatPackage, "Duration"},
+	"ipv4":          {"net/netip", "Addr"},
+	"ipv6":          {"net/netip", "Addr"},
+	"regex":         {formatPackage, "Regexp"},
+	"uri":           {formatPackage, "URI"},
+	"uri-reference": {formatPackage, "URIReference"},
+	"uuid":          {formatPackage, "UUID"},
+}
+
+// formatCheck returns the condition under which x, a value of the Go type for the format, is not
+// of the format (and the message for the error), for formats whose Go type also holds other values.
+// For example, netip.Addr holds both IPv4 and IPv6 addresses. An absent (zero) value is of the
+// format unless omitempty is false.
+func formatCheck(format jsonschema.Format, x string, omitempty bool) (cond, message string, ok bool) {
+	if strings.HasPrefix(x, "*") {
+		x = "(" + x + ")"
+	}
+	switch format {
+	case "ipv4":
+		cond, message = fmt.Sprintf("!%s.Is4()", x), "must be an IPv4 address"
+	case "ipv6":
+		// JSON Schema's "ipv6" format does not allow a zone (such as "fe80::1%eth0").
+		cond, message = fmt.Sprintf("(!%[1]s.Is6() || %[1]s.Zone() != \"\")", x), "must be an IPv6 address"
+	default:
+		return "", "", false
+	}
+	if omitempty {
+		cond = fmt.Sprintf("%s.IsValid() && %s", x, cond)
+	}
+	return cond, message, true
+}
+
+// SupportedFormats returns the values of the "format" keyword that can be listed in
+// Options.Formats.
+func SupportedFormats() []string {
+	formats := make([]string, 0, len(formatGoTypes))
+	for format := range formatGoTypes {
+		formats = append(formats, string(format))
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// formatTypeExpr returns the Go type that represents values of the string schema, if its "format"
+// is enabled in the options.
+func (g *generator) formatTypeExpr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec, bool) {
+	if schema.Format == nil {
+		return nil, nil, false
+	}
+	goType, ok := formatGoTypes[*schema.Format]
+	if !ok {
+		return nil, nil, false
+	}
+	enabled := false
+	for _, format := range g.opts.Formats {
+		if format == string(*schema.Format) {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return nil, nil, false
+	}
+	expr := &ast.SelectorExpr{X: ast.NewIdent(path.Base(goType.importPath)), Sel: ast.NewIdent(goType.name)}
+	return expr, importSpecs(goType.importPath), true
+}
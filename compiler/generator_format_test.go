@@ -0,0 +1,64 @@
+package compiler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	testdata_format "github.com/sourcegraph/go-jsonschema/compiler/testdata/format"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// TestFormatTypes depends on the generated ./testdata/format/want.go file, which you can overwrite
+// with the latest generated code by running `go test -test.write-want`.
+func TestFormatTypes(t *testing.T) {
+	const data = `{"addresses":["10.0.0.1"],"homepage":"https://example.com","id":"f81d4fae-7dec-11d0-a765-00a0c91e6bf6","link":"/a#b","pattern":"^a$","time":"2006-01-02T15:04:05Z","timeout":"PT1M"}`
+	var v testdata_format.Event
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC); !v.Time.Equal(want) {
+		t.Errorf("got time %v, want %v", v.Time, want)
+	}
+	if !v.Addresses[0].Is4() {
+		t.Errorf("got address %v, want IPv4 address", v.Addresses[0])
+	}
+	if !v.Pattern.MatchString("a") {
+		t.Error("got pattern not matching")
+	}
+	if time.Duration(*v.Timeout) != time.Minute {
+		t.Errorf("got timeout %v, want 1m", time.Duration(*v.Timeout))
+	}
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != data {
+		t.Errorf("got %s, want %s", got, data)
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("got Validate error %v, want nil", err)
+	}
+
+	// netip.Addr holds addresses of both families, so the family is checked by Validate.
+	var wrongFamily testdata_format.Event
+	if err := json.Unmarshal([]byte(`{"addresses":["10.0.0.1","::1"],"ipv6Address":"10.0.0.2"}`), &wrongFamily); err != nil {
+		t.Fatal(err)
+	}
+	if err, want := wrongFamily.Validate(), "addresses/1: must be an IPv4 address\nipv6Address: must be an IPv6 address"; err == nil || err.Error() != want {
+		t.Errorf("got Validate error %v, want %q", err, want)
+	}
+
+	for _, data := range []string{`{"id":"x"}`, `{"time":"yesterday"}`, `{"addresses":["x"]}`, `{"homepage":"/a"}`, `{"pattern":"("}`, `{"timeout":"P1Y"}`} {
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("%s: got nil error, want error", data)
+		}
+	}
+}
+
+func TestCompileWithOptions_unsupportedFormat(t *testing.T) {
+	schema := &jsonschema.Schema{Type: jsonschema.PrimitiveTypeList{jsonschema.StringType}}
+	if _, _, err := CompileWithOptions([]*jsonschema.Schema{schema}, Options{Formats: []string{"email"}}); err == nil {
+		t.Error("got nil error, want error for unsupported format")
+	}
+}
@@ -0,0 +1,139 @@
+package compiler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// goIntegerTypes are the Go types that may represent integer schemas, and the range of each. The
+// size of uint and int depends on the platform, so they are listed with the largest range that they
+// may have (which is only used to omit checks of bounds that their range implies), and they are never
+// chosen by bounds.
+var goIntegerTypes = []goIntegerTypeInfo{
+	{"uint8", 0, math.MaxUint8},
+	{"uint16", 0, math.MaxUint16},
+	{"uint32", 0, math.MaxUint32},
+	{"uint64", 0, math.MaxUint64},
+	{"uint", 0, math.MaxUint64},
+	{"int8", math.MinInt8, math.MaxInt8},
+	{"int16", math.MinInt16, math.MaxInt16},
+	{"int32", math.MinInt32, math.MaxInt32},
+	{"int64", math.MinInt64, math.MaxInt64},
+	{"int", math.MinInt64, math.MaxInt64},
+}
+
+// goIntegerTypeInfo describes a Go integer type and its range.
+type goIntegerTypeInfo struct {
+	name     string
+	min, max float64
+}
+
+// lookupGoIntegerType returns the Go integer type with the given name (if any).
+func lookupGoIntegerType(name string) (goIntegerTypeInfo, bool) {
+	for _, t := range goIntegerTypes {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return goIntegerTypeInfo{}, false
+}
+
+// contains reports whether the integer v is in the range of t (and so is a valid constant of t).
+func (t goIntegerTypeInfo) contains(v float64) bool {
+	// The max of the 64-bit types is rounded up to a power of 2 as a float64 (and adding 1 leaves it
+	// unchanged), so compare with max+1 instead of max.
+	return v >= t.min && v < t.max+1
+}
+
+// format returns the Go literal for the integer v (which t contains).
+func (t goIntegerTypeInfo) format(v float64) string {
+	if t.min == 0 {
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return strconv.FormatInt(int64(v), 10)
+}
+
+// goIntegerType returns the Go type that represents values of the integer schema.
+//
+// The !go.integerType extension, if set, determines the type. Otherwise, if
+// Options.SizedIntegerTypes is set, the type is the smallest fixed-size Go integer type whose range
+// includes the schema's bounds (minimum, maximum, exclusiveMinimum, and exclusiveMaximum):
+//
+//   - a non-negative integer with a maximum is a uint8, uint16, uint32, or uint64
+//   - a non-negative integer without a maximum is a uint64
+//   - an integer with both bounds is an int8, int16, int32, or int64
+//   - an integer with only one bound is an int64 if that bound is outside the range of int32
+//
+// Otherwise (or if no Go integer type's range includes the bounds, which is reported by
+// Options.Warn) the type is int (or the type that Options.BuiltinTypes maps integers to).
+func (g *generator) goIntegerType(schema *jsonschema.Schema) (string, error) {
+	if schema.Go != nil && schema.Go.IntegerType != "" {
+		for _, t := range goIntegerTypes {
+			if t.name == schema.Go.IntegerType {
+				return t.name, nil
+			}
+		}
+		return "", fmt.Errorf("invalid !go.integerType %q (must be a Go integer type such as int64 or uint8)", schema.Go.IntegerType)
+	}
+	if !g.opts.SizedIntegerTypes {
+		return g.goBuiltinType(jsonschema.IntegerType), nil
+	}
+
+	var min, max *float64
+	if schema.Minimum != nil {
+		v := math.Ceil(*schema.Minimum)
+		min = &v
+	}
+	if schema.ExclusiveMinimum != nil {
+		if v := math.Floor(*schema.ExclusiveMinimum) + 1; min == nil || v > *min {
+			min = &v
+		}
+	}
+	if schema.Maximum != nil {
+		v := math.Floor(*schema.Maximum)
+		max = &v
+	}
+	if schema.ExclusiveMaximum != nil {
+		if v := math.Ceil(*schema.ExclusiveMaximum) - 1; max == nil || v < *max {
+			max = &v
+		}
+	}
+
+	// fits reports whether the range of the Go integer type includes each of the bounds. The bounds
+	// are float64s, so a bound that rounds to the maximum of a 64-bit type (such as
+	// 18446744073709551615 for uint64) is taken to be that maximum.
+	fits := func(name string) bool {
+		for _, t := range goIntegerTypes {
+			if t.name == name {
+				in := func(v *float64) bool { return v == nil || (*v >= t.min && *v <= t.max) }
+				return in(min) && in(max)
+			}
+		}
+		panic("unknown Go integer type " + name)
+	}
+	var names []string // the candidate types (smallest first)
+	switch {
+	case min != nil && *min >= 0:
+		names = []string{"uint8", "uint16", "uint32", "uint64"}
+		if max == nil {
+			names = []string{"uint64"}
+		}
+	case min != nil && max != nil:
+		names = []string{"int8", "int16", "int32", "int64"}
+	case (min != nil || max != nil) && !fits("int32"):
+		names = []string{"int64"}
+	}
+	for _, name := range names {
+		if fits(name) {
+			return name, nil
+		}
+	}
+	builtin := g.goBuiltinType(jsonschema.IntegerType)
+	if len(names) > 0 {
+		g.warnf("integer schema at %q allows values outside the range of every Go integer type, so it is represented by %s", jsonschema.EncodeReferenceTokens(g.schemas[schema].rel), builtin)
+	}
+	return builtin, nil
+}
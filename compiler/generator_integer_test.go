@@ -0,0 +1,75 @@
+package compiler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestGoIntegerType(t *testing.T) {
+	tests := map[string]string{
+		`{}`:                                           "int",
+		`{"minimum":0,"maximum":255}`:                  "uint8",
+		`{"minimum":0,"exclusiveMaximum":256}`:         "uint8",
+		`{"minimum":0.5,"maximum":255.5}`:              "uint8",
+		`{"exclusiveMinimum":-1,"maximum":256}`:        "uint16",
+		`{"minimum":0,"maximum":18446744073709551615}`: "uint64",
+		`{"minimum":0}`:                                "uint64",
+		`{"minimum":-1,"maximum":1}`:                   "int8",
+		`{"minimum":-40000,"maximum":1}`:               "int32",
+		`{"minimum":-1,"maximum":3000000000}`:          "int64",
+		`{"maximum":10}`:                               "int",
+		`{"maximum":-3000000000}`:                      "int64",
+		`{"minimum":-3000000000}`:                      "int64",
+		`{"minimum":0,"!go":{"integerType":"int32"}}`:  "int32",
+	}
+	for schemaJSON, want := range tests {
+		t.Run(schemaJSON, func(t *testing.T) {
+			var schema jsonschema.Schema
+			if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+				t.Fatal(err)
+			}
+			g := &generator{opts: Options{SizedIntegerTypes: true}}
+			got, err := g.goIntegerType(&schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+
+	t.Run("no Go integer type fits", func(t *testing.T) {
+		for _, schemaJSON := range []string{`{"minimum":0,"maximum":1e20}`, `{"minimum":-1,"maximum":1e19}`, `{"minimum":-1e19}`, `{"minimum":1e20}`} {
+			var schema jsonschema.Schema
+			if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+				t.Fatal(err)
+			}
+			var warnings []string
+			g := &generator{opts: Options{SizedIntegerTypes: true, Warn: func(message string) { warnings = append(warnings, message) }}}
+			got, err := g.goIntegerType(&schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "int" {
+				t.Errorf("%s: got %s, want int", schemaJSON, got)
+			}
+			if want := []string{`integer schema at "" allows values outside the range of every Go integer type, so it is represented by int`}; !reflect.DeepEqual(warnings, want) {
+				t.Errorf("%s: got warnings %q, want %q", schemaJSON, warnings, want)
+			}
+		}
+	})
+
+	t.Run("invalid !go.integerType", func(t *testing.T) {
+		var schema jsonschema.Schema
+		if err := json.Unmarshal([]byte(`{"!go":{"integerType":"float64"}}`), &schema); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := (&generator{}).goIntegerType(&schema); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
@@ -4,28 +4,146 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
-func (g *generator) emitStructAdditionalField(schema *jsonschema.Schema, goName string, fields []field) (*ast.Field, []ast.Decl, []*ast.ImportSpec, error) {
-	additionalField := &ast.Field{
-		Names: []*ast.Ident{ast.NewIdent("Additional")},
-		Type:  &ast.MapType{Key: ast.NewIdent("string"), Value: anyType},
-		Tag: &ast.BasicLit{
-			Kind:  token.STRING,
-			Value: fmt.Sprintf("`json:%q`", "-"),
-		},
-		Comment: &ast.CommentGroup{
-			List: []*ast.Comment{{Text: " // additionalProperties not explicitly defined in the schema"}},
-		},
+// hasExtraPropertiesFields reports whether the Go struct type for schema has fields (and
+// MarshalJSON and UnmarshalJSON methods that support them) for the properties that are not among
+// its "properties": those that match its patternProperties and, if it allows additionalProperties,
+// the others.
+func hasExtraPropertiesFields(schema *jsonschema.Schema) bool {
+	return allowsAdditionalProperties(schema) || (schema.PatternProperties != nil && len(*schema.PatternProperties) > 0)
+}
+
+// extraPropertiesField is a Go struct field that holds the properties that match some of the
+// schema's patternProperties (or, for the Additional field, the additionalProperties).
+type extraPropertiesField struct {
+	Name       string
+	ValueType  string // the Go type of the map values
+	PatternVar string // the variable that holds the regexp that matches the properties that the field holds (see patternVar; empty for Additional)
+}
+
+// patternPropertiesFields returns the Go struct fields for the schema's patternProperties: a
+// single PatternProperties map if the Go types of all of the patterns' schemas are the same, and
+// otherwise a PatternPropertiesN map for the Nth pattern (in sorted order).
+//
+// It returns an error if a pattern is not supported by Go's regexp package (see patternVar), because
+// the properties that match it could not be unmarshaled into the right field.
+func (g *generator) patternPropertiesFields(schema *jsonschema.Schema, goName string) ([]*ast.Field, []extraPropertiesField, []*ast.ImportSpec, error) {
+	if schema.PatternProperties == nil || len(*schema.PatternProperties) == 0 {
+		return nil, nil, nil, nil
+	}
+	patterns := sortedKeys(*schema.PatternProperties)
+	valueTypes := make([]ast.Expr, len(patterns))
+	var imports []*ast.ImportSpec
+	same := true
+	for i, pattern := range patterns {
+		var imports1 []*ast.ImportSpec
+		var err error
+		valueTypes[i], imports1, err = g.expr((*schema.PatternProperties)[pattern])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get type expression for patternProperties %q: %w", pattern, err)
+		}
+		imports = append(imports, imports1...)
+		same = same && types.ExprString(valueTypes[i]) == types.ExprString(valueTypes[0])
+	}
+
+	newField := func(name string, valueType ast.Expr, patterns []string) (*ast.Field, extraPropertiesField, error) {
+		quoted := make([]string, len(patterns))
+		regexps := make([]string, len(patterns))
+		for i, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, extraPropertiesField{}, fmt.Errorf("patternProperties %q is not supported by Go's regexp package: %w", pattern, err)
+			}
+			quoted[i] = strconv.Quote(pattern)
+			regexps[i] = "(?:" + pattern + ")"
+		}
+		pattern := patterns[0]
+		if len(patterns) > 1 {
+			pattern = strings.Join(regexps, "|")
+		}
+		patternVar, err := g.patternVar(goName, pattern)
+		if err != nil {
+			return nil, extraPropertiesField{}, err
+		}
+		return &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(name)},
+			Type:  &ast.MapType{Key: ast.NewIdent("string"), Value: valueType},
+			Tag:   &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("`json:%q`", "-")},
+			Comment: &ast.CommentGroup{
+				List: []*ast.Comment{{Text: " // properties matching " + strings.Join(quoted, ", ")}},
+			},
+		}, extraPropertiesField{
+			Name:       name,
+			ValueType:  types.ExprString(valueType),
+			PatternVar: patternVar,
+		}, nil
+	}
+	if same {
+		astField, field, err := newField("PatternProperties", valueTypes[0], patterns)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return []*ast.Field{astField}, []extraPropertiesField{field}, imports, nil
+	}
+	astFields := make([]*ast.Field, len(patterns))
+	fields := make([]extraPropertiesField, len(patterns))
+	for i, pattern := range patterns {
+		var err error
+		astFields[i], fields[i], err = newField("PatternProperties"+strconv.Itoa(i), valueTypes[i], []string{pattern})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return astFields, fields, imports, nil
+}
+
+// emitStructAdditionalField returns the Go struct fields for the properties of schema that are not
+// among its "properties" (see hasExtraPropertiesFields), and MarshalJSON and UnmarshalJSON methods
+// for the Go struct type that support them.
+//
+// When unmarshaling, each property that is not among the schema's "properties" is stored in the
+// map for the first of the schema's patternProperties that it matches, or otherwise (if the schema
+// allows additionalProperties) in the Additional map.
+func (g *generator) emitStructAdditionalField(schema *jsonschema.Schema, goName string, fields []field) ([]*ast.Field, []ast.Decl, []*ast.ImportSpec, error) {
+	astFields, extraFields, imports, err := g.patternPropertiesFields(schema, goName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if allowsAdditionalProperties(schema) {
+		valueType, imports1, err := g.expr(schema.AdditionalProperties)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get type expression for additionalProperties: %w", err)
+		}
+		imports = append(imports, imports1...)
+		astFields = append(astFields, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("Additional")},
+			Type:  &ast.MapType{Key: ast.NewIdent("string"), Value: valueType},
+			Tag: &ast.BasicLit{
+				Kind:  token.STRING,
+				Value: fmt.Sprintf("`json:%q`", "-"),
+			},
+			Comment: &ast.CommentGroup{
+				List: []*ast.Comment{{Text: " // additionalProperties not explicitly defined in the schema"}},
+			},
+		})
+		extraFields = append(extraFields, extraPropertiesField{Name: "Additional", ValueType: types.ExprString(valueType)})
 	}
 
-	// Generate MarshalJSON and UnmarshalJSON methods on the Go union type.
+	// Generate MarshalJSON and UnmarshalJSON methods on the Go struct type.
 	templateData := map[string]any{
-		"fields": fields,
-		"goName": goName,
+		"fields":      fields,
+		"goName":      goName,
+		"extraFields": extraFields,
+		// Additional properties of any type (and no patternProperties) are unmarshaled without
+		// json.RawMessage.
+		"untyped": len(extraFields) == 1 && extraFields[0].Name == "Additional" && extraFields[0].ValueType == anyType.Name,
 	}
 	marshalJSONDecl, err := parseFuncLitToFuncDecl(executeTemplate(structAdditionalFieldMarshalJSONTemplate, templateData))
 	if err != nil {
@@ -38,18 +156,29 @@ func (g *generator) emitStructAdditionalField(schema *jsonschema.Schema, goName
 	makeMethod(marshalJSONDecl, ast.NewIdent(goName), "MarshalJSON")
 	makeMethod(unmarshalJSONDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "UnmarshalJSON")
 
-	return additionalField, []ast.Decl{marshalJSONDecl, unmarshalJSONDecl},
-		importSpecs("encoding/json"),
-		nil
+	imports = append(imports, importSpecs("encoding/json")...)
+	if !templateData["untyped"].(bool) {
+		imports = append(imports, importSpecs("fmt")...)
+	}
+	return astFields, []ast.Decl{marshalJSONDecl, unmarshalJSONDecl}, imports, nil
 }
 
 var (
 	structAdditionalFieldMarshalJSONTemplate = template.Must(template.New("").Parse(`
 func() ([]byte, error) {
+	{{- if .untyped}}
 	m := make(map[string]any, len(v.Additional))
 	for k, v := range v.Additional {
 		m[k] = v
 	}
+	{{- else}}
+	m := make(map[string]any)
+	{{- range .extraFields}}
+	for k, v := range v.{{.Name}} {
+		m[k] = v
+	}
+	{{- end}}
+	{{- end}}
 
 	type wrapper {{.goName}}
 	b, err := json.Marshal(wrapper(v))
@@ -77,7 +206,13 @@ func(data []byte) error {
 	}
 	*v = {{.goName}}(s)
 
+	{{- if .untyped}}
+
 	var m map[string]any
+	{{- else}}
+
+	var m map[string]json.RawMessage
+	{{- end}}
 	if err := json.Unmarshal(data, &m); err != nil {
 		return err
 	}
@@ -85,13 +220,70 @@ func(data []byte) error {
 	delete(m, {{printf "%q" .JSONName}})
 	{{- end}}
 
+	{{- if .untyped}}
+
 	if len(m) > 0 {
 		v.Additional = make(map[string]any, len(m))
 	}
 	for k, vv := range m {
 		v.Additional[k] = vv
 	}
+	{{- else}}
+
+	for k, raw := range m {
+		{{- range .extraFields}}
+		{{- if .PatternVar}}
+		if {{.PatternVar}}.MatchString(k) {
+			var vv {{.ValueType}}
+			if err := json.Unmarshal(raw, &vv); err != nil {
+				return fmt.Errorf("property %q: %w", k, err)
+			}
+			if v.{{.Name}} == nil {
+				v.{{.Name}} = make(map[string]{{.ValueType}})
+			}
+			v.{{.Name}}[k] = vv
+			continue
+		}
+		{{- else}}
+		var vv {{.ValueType}}
+		if err := json.Unmarshal(raw, &vv); err != nil {
+			return fmt.Errorf("property %q: %w", k, err)
+		}
+		if v.{{.Name}} == nil {
+			v.{{.Name}} = make(map[string]{{.ValueType}})
+		}
+		v.{{.Name}}[k] = vv
+		{{- end}}
+		{{- end}}
+	}
+	{{- end}}
 	return nil
 }
 `))
 )
+
+// patternPropertiesMapValueExpr returns the value type of the Go map type for an object schema
+// with patternProperties and no properties, given the Go type for its additionalProperties
+// (additionalType). It is the Go type of all of the patternProperties' schemas (and of
+// additionalProperties, unless additional properties are disallowed) if they are all the same, and
+// otherwise any.
+func (g *generator) patternPropertiesMapValueExpr(schema *jsonschema.Schema, additionalType ast.Expr, additionalImports []*ast.ImportSpec) (ast.Expr, []*ast.ImportSpec, error) {
+	var valueType ast.Expr
+	var imports []*ast.ImportSpec
+	if !(schema.AdditionalProperties != nil && schema.AdditionalProperties.IsNegated) {
+		valueType, imports = additionalType, additionalImports
+	}
+	for _, pattern := range sortedKeys(*schema.PatternProperties) {
+		typeExpr, imports1, err := g.expr((*schema.PatternProperties)[pattern])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get type expression for patternProperties %q: %w", pattern, err)
+		}
+		if valueType == nil {
+			valueType = typeExpr
+		} else if types.ExprString(valueType) != types.ExprString(typeExpr) {
+			return anyType, nil, nil
+		}
+		imports = append(imports, imports1...)
+	}
+	return valueType, imports, nil
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	objectwithprops "github.com/sourcegraph/go-jsonschema/compiler/testdata/object-with-props"
+	patternproperties "github.com/sourcegraph/go-jsonschema/compiler/testdata/pattern-properties"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 func TestAdditionalProperties(t *testing.T) {
@@ -53,3 +56,68 @@ func TestAdditionalProperties(t *testing.T) {
 		})
 	}
 }
+
+func TestPatternProperties(t *testing.T) {
+	tests := []struct {
+		json  string
+		value patternproperties.PatternProperties
+	}{
+		{
+			json: `{"counts":{"c1":1},"labels":{"A":"a","b":"b","default":"d"},"n-1":1.5,"name":"N","x-a":"A","y":true}`,
+			value: patternproperties.PatternProperties{
+				Counts:             map[string]int{"c1": 1},
+				Labels:             &patternproperties.Labels{Default: "d", PatternProperties: map[string]string{"A": "a", "b": "b"}},
+				Name:               "N",
+				PatternProperties0: map[string]float64{"n-1": 1.5},
+				PatternProperties1: map[string]string{"x-a": "A"},
+				Additional:         map[string]bool{"y": true},
+			},
+		},
+		{
+			json:  `{"name":"N"}`,
+			value: patternproperties.PatternProperties{Name: "N"},
+		},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			gotJSON, err := json.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(gotJSON) != test.json {
+				t.Errorf("got %s, want %s", gotJSON, test.json)
+			}
+
+			var gotValue patternproperties.PatternProperties
+			if err := json.Unmarshal(gotJSON, &gotValue); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotValue, test.value) {
+				t.Errorf("got %+v, want %+v", gotValue, test.value)
+			}
+		})
+	}
+
+	t.Run("type mismatch", func(t *testing.T) {
+		var v patternproperties.PatternProperties
+		if err := json.Unmarshal([]byte(`{"x-a":1}`), &v); err == nil {
+			t.Error("got nil error, want error for property whose value doesn't match its pattern's type")
+		}
+	})
+}
+
+func TestPatternProperties_unsupportedPattern(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{
+  "title": "t",
+  "type": "object",
+  "properties": {"a": {"type": "string"}},
+  "patternProperties": {"^(?!x)": {"type": "string"}}
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := Compile([]*jsonschema.Schema{&schema})
+	if want := "patternProperties \"^(?!x)\" is not supported by Go's regexp package: error parsing regexp: invalid or unsupported Perl syntax: `(?!`"; err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("got error %v, want it to end with %q", err, want)
+	}
+}
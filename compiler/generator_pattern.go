@@ -0,0 +1,54 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// patternVar returns the name of the package-level variable that holds the compiled regexp for
+// pattern, for use in the generated code for the Go type named goName, so that the regexp is
+// compiled only once (see patternVarDecls).
+//
+// It returns an error if Go's regexp package (which uses RE2 syntax) can't compile pattern. Some
+// valid JSON Schema patterns (which use ECMA-262 syntax), such as those with lookahead, are not
+// supported.
+func (g *generator) patternVar(goName, pattern string) (string, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", err
+	}
+	if g.patterns == nil {
+		g.patterns = map[string][]string{}
+	}
+	i := slices.Index(g.patterns[goName], pattern)
+	if i == -1 {
+		i = len(g.patterns[goName])
+		g.patterns[goName] = append(g.patterns[goName], pattern)
+	}
+	return "pattern" + goName + strconv.Itoa(i), nil
+}
+
+// patternVarDecls returns the declarations of the variables returned by patternVar.
+func (g *generator) patternVarDecls() ([]ast.Decl, []*ast.ImportSpec) {
+	if len(g.patterns) == 0 {
+		return nil, nil
+	}
+	var decls []ast.Decl
+	for _, goName := range sortedKeys(g.patterns) {
+		for i, pattern := range g.patterns[goName] {
+			decls = append(decls, &ast.GenDecl{
+				Tok: token.VAR,
+				Specs: []ast.Spec{&ast.ValueSpec{
+					Names: []*ast.Ident{ast.NewIdent("pattern" + goName + strconv.Itoa(i))},
+					Values: []ast.Expr{&ast.CallExpr{
+						Fun:  &ast.SelectorExpr{X: ast.NewIdent("regexp"), Sel: ast.NewIdent("MustCompile")},
+						Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(pattern)}},
+					}},
+				}},
+			})
+		}
+	}
+	return decls, importSpecs("regexp")
+}
@@ -11,10 +11,44 @@ import (
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
-func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
+// taggedUnion describes the Go union type for a schema whose oneOf branches are object schemas
+// that are discriminated by the value of a common property.
+type taggedUnion struct {
+	schemas              []*jsonschema.Schema // the oneOf branches (with $refs resolved)
+	discriminantPropName string
+	discriminantValues   []string // the discriminant property's value in each branch
+}
+
+// isTaggedUnionType reports whether schema is represented by a Go union type (see
+// emitTaggedUnionType). That is the case if schema has the !go.taggedUnionType extension, or if
+// Options.InferTaggedUnionTypes is set and a discriminant property is found for schema's oneOf
+// branches (which must all be $refs or have a title, so that their Go types have distinct names).
+func (g *generator) isTaggedUnionType(schema *jsonschema.Schema) bool {
+	if schema.Go != nil && schema.Go.TaggedUnionType {
+		return true
+	}
+	if !g.opts.InferTaggedUnionTypes || len(schema.OneOf) == 0 || (schema.Go != nil && schema.Go.TypeName != "") {
+		return false
+	}
+	if _, err := g.findTaggedUnion(schema); err != nil {
+		return false
+	}
+	for _, s := range schema.OneOf {
+		if s.Reference == nil && s.Title == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// findTaggedUnion finds the discriminant property for schema's oneOf branches: a property that all
+// of the branches require and whose value in each branch is a distinct string (given by "const" or
+// a single-valued "enum"). It returns an error if there is no such property or if there are
+// multiple.
+func (g *generator) findTaggedUnion(schema *jsonschema.Schema) (*taggedUnion, error) {
 	// Check that this schema can use the !go.taggedUnionType extension.
 	if len(schema.Type) >= 2 || (len(schema.Type) == 1 && schema.Type[0] != jsonschema.ObjectType) || len(schema.OneOf) == 0 {
-		return nil, nil, errors.New("invalid schema for use with !go.taggedUnionType extension")
+		return nil, errors.New("invalid schema for use with !go.taggedUnionType extension")
 	}
 
 	// Next, try to find the discriminant property.
@@ -23,8 +57,8 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 		if s.Reference != nil {
 			s = g.resolutions[s]
 		}
-		if len(s.Type) != 1 || s.Type[0] != jsonschema.ObjectType || s.Properties == nil || len(*s.Properties) == 0 {
-			return nil, nil, errors.New("invalid oneOf schema for use with !go.taggedUnionType (must be an object with properties)")
+		if s == nil || g.isExternal(s) || len(s.Type) != 1 || s.Type[0] != jsonschema.ObjectType || s.Properties == nil || len(*s.Properties) == 0 {
+			return nil, errors.New("invalid oneOf schema for use with !go.taggedUnionType (must be an object with properties)")
 		}
 		oneOfSchemas[i] = s
 	}
@@ -42,16 +76,43 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 		}
 	}
 	if len(commonProperties) == 0 {
-		return nil, nil, errors.New("no discriminant property found for !go.taggedUnionType extension")
+		return nil, errors.New("no discriminant property found for !go.taggedUnionType extension")
 	}
-	if len(commonProperties) >= 2 {
-		return nil, nil, fmt.Errorf("multiple discriminant properties found for !go.taggedUnionType extension: %q", commonProperties)
+	if len(commonProperties) == 1 {
+		var discriminantPropName string
+		for discriminantPropName = range commonProperties { // get first (and only) map key
+		}
+		discriminantValues, err := taggedUnionDiscriminantValues(oneOfSchemas, discriminantPropName)
+		if err != nil {
+			return nil, err
+		}
+		return &taggedUnion{schemas: oneOfSchemas, discriminantPropName: discriminantPropName, discriminantValues: discriminantValues}, nil
 	}
-	var discriminantPropName string
-	for discriminantPropName = range commonProperties { // get first (and only) map key
+
+	// If there are multiple common properties, use the one (if any) that can discriminate.
+	var union *taggedUnion
+	var candidates []string
+	for _, name := range sortedKeys(commonProperties) {
+		discriminantValues, err := taggedUnionDiscriminantValues(oneOfSchemas, name)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, name)
+		union = &taggedUnion{schemas: oneOfSchemas, discriminantPropName: name, discriminantValues: discriminantValues}
+	}
+	switch {
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("none of the common properties %q can discriminate for !go.taggedUnionType extension", sortedKeys(commonProperties))
+	case len(candidates) >= 2:
+		return nil, fmt.Errorf("multiple discriminant properties found for !go.taggedUnionType extension: %q", candidates)
 	}
-	// Ensure the common property can discriminate between the schemas (no value satisfies multiple
-	// of the oneOf schemas).
+	return union, nil
+}
+
+// taggedUnionDiscriminantValues returns the value of the discriminant property in each of the
+// oneOf schemas. It returns an error if the property can't discriminate between the schemas (or if
+// some value satisfies multiple of the schemas).
+func taggedUnionDiscriminantValues(oneOfSchemas []*jsonschema.Schema, discriminantPropName string) ([]string, error) {
 	constValuesToSchema := make(map[string]*jsonschema.Schema, len(oneOfSchemas))
 	discriminantValues := make([]string, 0, len(oneOfSchemas))
 	for _, s := range oneOfSchemas {
@@ -65,11 +126,12 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 			}
 		}
 		if !required {
-			return nil, nil, fmt.Errorf("invalid oneOf schema for !go.taggedUnionType extension (discriminant property %q must be required)", discriminantPropName)
+			return nil, fmt.Errorf("invalid oneOf schema for !go.taggedUnionType extension (discriminant property %q must be required)", discriminantPropName)
 		}
 
-		if len(prop.Type) != 1 || prop.Type[0] != jsonschema.StringType {
-			return nil, nil, errors.New("invalid oneOf schema discriminant prop type for !go.taggedUnionType extension (must be string type)")
+		// The discriminant property may omit "type", because its const or enum value is a string.
+		if len(prop.Type) > 1 || (len(prop.Type) == 1 && prop.Type[0] != jsonschema.StringType) {
+			return nil, errors.New("invalid oneOf schema discriminant prop type for !go.taggedUnionType extension (must be string type)")
 		}
 		var constVal *any
 		if prop.Const != nil {
@@ -80,23 +142,34 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 				v := (any(enumVal))
 				constVal = &v
 			} else if !reflect.DeepEqual(*constVal, enumVal) {
-				return nil, nil, fmt.Errorf("invalid oneOf schema discriminant prop enum value for !go.taggedUnionType extension (must have exactly 1 unique string value, got %q != %q)", *constVal, enumVal)
+				return nil, fmt.Errorf("invalid oneOf schema discriminant prop enum value for !go.taggedUnionType extension (must have exactly 1 unique string value, got %q != %q)", *constVal, enumVal)
 			}
 		}
 		if constVal == nil {
-			return nil, nil, fmt.Errorf("no oneOf schema discriminant prop enum value for !go.taggedUnionType extension (must have either const or enum)")
+			return nil, fmt.Errorf("no oneOf schema discriminant prop enum value for !go.taggedUnionType extension (must have either const or enum)")
 		}
 		switch ev := (*constVal).(type) {
 		case string:
 			if _, seen := constValuesToSchema[ev]; seen {
-				return nil, nil, fmt.Errorf("invalid oneOf schema discriminant prop const value for !go.taggedUnionType extension (value %q is allowed by other type)", ev)
+				return nil, fmt.Errorf("invalid oneOf schema discriminant prop const value for !go.taggedUnionType extension (value %q is allowed by other type)", ev)
 			}
 			constValuesToSchema[ev] = s
 			discriminantValues = append(discriminantValues, ev)
 		default:
-			return nil, nil, fmt.Errorf("invalid oneOf schema discriminant prop const value for !go.taggedUnionType extension (got %T not string)", ev)
+			return nil, fmt.Errorf("invalid oneOf schema discriminant prop const value for !go.taggedUnionType extension (got %T not string)", ev)
 		}
 	}
+	return discriminantValues, nil
+}
+
+// emitTaggedUnionType emits a Go union type for schema, with a pointer field for each of its oneOf
+// branches (see findTaggedUnion) and MarshalJSON and UnmarshalJSON methods.
+func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
+	union, err := g.findTaggedUnion(schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	oneOfSchemas, discriminantPropName, discriminantValues := union.schemas, union.discriminantPropName, union.discriminantValues
 
 	imports := importSpecs("fmt", "encoding/json", "errors")
 
@@ -118,7 +191,7 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 			Type:  &ast.StarExpr{X: typeExpr},
 		}
 	}
-	goName, err := goNameForSchema(schema, g.schemas[schema])
+	goName, err := g.goNameForSchema(schema, g.schemas[schema])
 	if err != nil {
 		return nil, nil, err
 	}
@@ -149,9 +222,17 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 	makeMethod(marshalJSONDecl, ast.NewIdent(goName), "MarshalJSON")
 	makeMethod(unmarshalJSONDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "UnmarshalJSON")
 
-	return []ast.Decl{typeDecl, marshalJSONDecl, unmarshalJSONDecl},
-		imports,
-		nil
+	decls := []ast.Decl{typeDecl, marshalJSONDecl, unmarshalJSONDecl}
+	if g.opts.EmitValidateMethods {
+		validateDecl, imports1, err := g.emitTaggedUnionValidateMethod(goName, fieldNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		decls = append(decls, validateDecl)
+		imports = append(imports, imports1...)
+	}
+
+	return decls, imports, nil
 }
 
 var (
@@ -0,0 +1,39 @@
+package compiler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestInferTaggedUnionTypes_untitledBranches(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{
+  "title": "t",
+  "type": "object",
+  "properties": {
+    "u": {
+      "oneOf": [
+        {"type": "object", "required": ["kind"], "properties": {"kind": {"type": "string", "const": "a"}}},
+        {"type": "object", "required": ["kind"], "properties": {"kind": {"type": "string", "const": "b"}}}
+      ]
+    }
+  }
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	_, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+		InferTaggedUnionTypes: true,
+		Warn:                  func(message string) { warnings = append(warnings, message) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`oneOf at "properties/u" has a discriminant property, but no Go union type will be emitted for it because not all of its branches are $refs or have a title`}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+}
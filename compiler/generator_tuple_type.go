@@ -0,0 +1,257 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"text/template"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// isTupleType reports whether schema is an array schema with positional item schemas (given by
+// "prefixItems" or by an "items" array), which is represented by a Go tuple type (see
+// emitTupleType).
+func (g *generator) isTupleType(schema *jsonschema.Schema) bool {
+	return isTypeOrNull(schema, jsonschema.ArrayType) && len(tupleItems(schema)) > 0
+}
+
+// tupleItems returns the positional item schemas of the array schema.
+func tupleItems(schema *jsonschema.Schema) []*jsonschema.Schema {
+	if len(schema.PrefixItems) > 0 {
+		return schema.PrefixItems
+	}
+	if schema.Items != nil {
+		return schema.Items.Schemas
+	}
+	return nil
+}
+
+// tupleAdditionalItems returns the schema for the items after the positional items of the array
+// schema: "items" (with "prefixItems") or "additionalItems" (with an "items" array). If the
+// schema is absent, any additional items are allowed, and it returns the empty schema.
+func tupleAdditionalItems(schema *jsonschema.Schema) *jsonschema.Schema {
+	var additional *jsonschema.Schema
+	if len(schema.PrefixItems) > 0 {
+		if schema.Items != nil {
+			additional = schema.Items.Schema
+		}
+	} else {
+		additional = schema.AdditionalItems
+	}
+	if additional == nil {
+		return &jsonschema.Schema{IsEmpty: true}
+	}
+	return additional
+}
+
+// emitTupleType emits a Go struct type for the array schema with a field for each positional item
+// (and, if the schema allows additional items, an Additional slice field for the items after
+// them), and MarshalJSON and UnmarshalJSON methods that encode the struct as a JSON array.
+//
+// The positional items before the schema's minItems are required. The fields for the other
+// positional items are pointers (or other nilable types), so that an absent item is nil.
+func (g *generator) emitTupleType(schema *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
+	goName, err := g.goNameForSchema(schema, g.schemas[schema])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := tupleItems(schema)
+	var minItems int
+	if schema.MinItems != nil {
+		minItems = min(int(*schema.MinItems), len(items))
+	}
+
+	type tupleField struct {
+		Name     string
+		Index    int
+		Required bool
+		schema   *jsonschema.Schema
+		typeExpr ast.Expr
+	}
+	var imports []*ast.ImportSpec
+	fields := make([]tupleField, len(items))
+	fieldNames := map[string]struct{}{"Additional": {}}
+	for i, item := range items {
+		typeExpr, fieldImports, err := g.expr(item)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get type expression for tuple item %d: %w", i, err)
+		}
+		imports = append(imports, fieldImports...)
+		required := i < minItems
+		if !required && !isNilable(typeExpr) {
+			typeExpr = &ast.StarExpr{X: typeExpr}
+		}
+
+		name := "Item" + strconv.Itoa(i)
+		if resolved := g.resolve(item); resolved.Title != nil {
+			if title := toGoName(*resolved.Title, "Item_"); title != "" {
+				if _, ok := fieldNames[title]; !ok {
+					name = title
+				}
+			}
+		}
+		fieldNames[name] = struct{}{}
+		fields[i] = tupleField{Name: name, Index: i, Required: required, schema: item, typeExpr: typeExpr}
+	}
+
+	structFields := make([]*ast.Field, len(fields))
+	for i, f := range fields {
+		structFields[i] = &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(f.Name)},
+			Doc:   docForSchema(f.schema, f.Name),
+			Type:  f.typeExpr,
+		}
+	}
+	additional := tupleAdditionalItems(schema)
+	var additionalType ast.Expr
+	if !additional.IsNegated {
+		elt, imports1, err := g.arrayElemExpr(additional)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get type expression for tuple additional items: %w", err)
+		}
+		imports = append(imports, imports1...)
+		additionalType = &ast.ArrayType{Elt: elt}
+		structFields = append(structFields, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("Additional")},
+			Type:  additionalType,
+			Comment: &ast.CommentGroup{
+				List: []*ast.Comment{{Text: " // items after the positional items"}},
+			},
+		})
+	}
+
+	decls := []ast.Decl{&ast.GenDecl{
+		Doc: docForSchema(schema, goName),
+		Tok: token.TYPE,
+		Specs: []ast.Spec{&ast.TypeSpec{
+			Name: ast.NewIdent(goName),
+			Type: &ast.StructType{Fields: &ast.FieldList{List: structFields}},
+		}},
+	}}
+
+	// Generate MarshalJSON and UnmarshalJSON methods on the Go tuple type.
+	templateData := map[string]any{
+		"goName":        goName,
+		"fields":        fields,
+		"minItems":      minItems,
+		"numItems":      len(items),
+		"hasAdditional": additionalType != nil,
+	}
+	marshalJSONDecl, err := parseFuncLitToFuncDecl(executeTemplate(tupleTypeMarshalJSONTemplate, templateData))
+	if err != nil {
+		return nil, nil, err
+	}
+	unmarshalJSONDecl, err := parseFuncLitToFuncDecl(executeTemplate(tupleTypeUnmarshalJSONTemplate, templateData))
+	if err != nil {
+		return nil, nil, err
+	}
+	makeMethod(marshalJSONDecl, ast.NewIdent(goName), "MarshalJSON")
+	makeMethod(unmarshalJSONDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "UnmarshalJSON")
+	decls = append(decls, marshalJSONDecl, unmarshalJSONDecl)
+	imports = append(imports, importSpecs("encoding/json", "fmt")...)
+
+	if g.opts.EmitValidateMethods {
+		c := validateCode{g: g, goName: goName, imports: map[string]struct{}{}}
+		for _, f := range fields {
+			if err := c.value(f.schema, f.typeExpr, "v."+f.Name, validatePath{format: strconv.Itoa(f.Index)}, !f.Required); err != nil {
+				return nil, nil, fmt.Errorf("failed to emit validation for tuple item %d: %w", f.Index, err)
+			}
+		}
+		if additionalType != nil {
+			i, e := c.newVar("i"), c.newVar("e")
+			err := c.block(fmt.Sprintf("for %s, %s := range v.Additional", i, e), func() error {
+				path := validatePath{format: "%d", args: []string{fmt.Sprintf("%d+%s", len(items), i)}}
+				return c.value(additional, additionalType.(*ast.ArrayType).Elt, e, path, false)
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to emit validation for tuple additional items: %w", err)
+			}
+		}
+		validateDecl, imports1, err := c.funcDecl(goName)
+		if err != nil {
+			return nil, nil, err
+		}
+		decls = append(decls, validateDecl)
+		imports = append(imports, imports1...)
+	}
+	return decls, imports, nil
+}
+
+var (
+	tupleTypeMarshalJSONTemplate = template.Must(template.New("").Parse(`
+func() ([]byte, error) {
+	items := make([]any, 0, {{.numItems}})
+	{{- range .fields}}
+	{{- if .Required}}
+	items = append(items, v.{{.Name}})
+	{{- else}}
+	if v.{{.Name}} != nil {
+		if len(items) != {{.Index}} {
+			return nil, fmt.Errorf("tuple item %d is set but item %d is not", {{.Index}}, len(items))
+		}
+		items = append(items, v.{{.Name}})
+	}
+	{{- end}}
+	{{- end}}
+	{{- if .hasAdditional}}
+	if len(v.Additional) > 0 && len(items) != {{.numItems}} {
+		return nil, fmt.Errorf("tuple additional items are set but item %d is not", len(items))
+	}
+	for _, item := range v.Additional {
+		items = append(items, item)
+	}
+	{{- end}}
+	return json.Marshal(items)
+}
+`))
+	tupleTypeUnmarshalJSONTemplate = template.Must(template.New("").Parse(`
+func(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	{{- if gt .minItems 0}}
+	if len(items) < {{.minItems}} {
+		return fmt.Errorf("{{.goName}} must have at least %d items, got %d", {{.minItems}}, len(items))
+	}
+	{{- end}}
+	{{- if not .hasAdditional}}
+	if len(items) > {{.numItems}} {
+		return fmt.Errorf("{{.goName}} must have at most %d items, got %d", {{.numItems}}, len(items))
+	}
+	{{- end}}
+	*v = {{.goName}}{}
+	{{- range .fields}}
+	{{- if .Required}}
+	if err := json.Unmarshal(items[{{.Index}}], &v.{{.Name}}); err != nil {
+		return fmt.Errorf("tuple item %d: %w", {{.Index}}, err)
+	}
+	{{- else}}
+	if len(items) > {{.Index}} {
+		if err := json.Unmarshal(items[{{.Index}}], &v.{{.Name}}); err != nil {
+			return fmt.Errorf("tuple item %d: %w", {{.Index}}, err)
+		}
+	}
+	{{- end}}
+	{{- end}}
+	{{- if .hasAdditional}}
+	if len(items) > {{.numItems}} {
+		additional, err := json.Marshal(items[{{.numItems}}:])
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(additional, &v.Additional); err != nil {
+			return fmt.Errorf("tuple additional items: %w", err)
+		}
+	}
+	{{- end}}
+	return nil
+}
+`))
+)
@@ -0,0 +1,253 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"text/template"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// union describes the Go union type for a schema whose anyOf (or oneOf) branches have
+// distinguishable JSON kinds.
+type union struct {
+	branches []unionBranch
+	nullable bool // whether a branch allows null (which is represented by the union type's zero value)
+}
+
+type unionBranch struct {
+	schema    *jsonschema.Schema
+	kind      jsonschema.PrimitiveType // the JSON kind (with integer as number) of the branch's values
+	fieldName string
+	fieldType ast.Expr
+	imports   []*ast.ImportSpec
+}
+
+// isUnionType reports whether schema is represented by a Go union type (see emitUnionType).
+func (g *generator) isUnionType(schema *jsonschema.Schema) bool {
+	u, err := g.findUnion(schema)
+	return u != nil && err == nil
+}
+
+// unionBranches returns schema's anyOf or oneOf branches, if it has exactly one of those keywords.
+func unionBranches(schema *jsonschema.Schema) []*jsonschema.Schema {
+	switch {
+	case len(schema.AnyOf) > 0 && len(schema.OneOf) == 0:
+		return schema.AnyOf
+	case len(schema.OneOf) > 0 && len(schema.AnyOf) == 0:
+		return schema.OneOf
+	}
+	return nil
+}
+
+// unionKeyword returns the keyword ("anyOf" or "oneOf") whose branches are the branches of the
+// union.
+func unionKeyword(schema *jsonschema.Schema) string {
+	if len(schema.AnyOf) > 0 {
+		return "anyOf"
+	}
+	return "oneOf"
+}
+
+// findUnion returns the Go union type for schema if Options.EmitUnionTypes is set and schema's
+// anyOf (or oneOf) branches can be distinguished by the kind of JSON value (string, number,
+// boolean, array, or object) they allow. If schema has no type and has anyOf (or oneOf) branches
+// that can't be distinguished, it returns an error that describes why. Otherwise it returns nil.
+func (g *generator) findUnion(schema *jsonschema.Schema) (*union, error) {
+	branches := unionBranches(schema)
+	if !g.opts.EmitUnionTypes || len(branches) == 0 || len(schema.Type) != 0 || (schema.Go != nil && schema.Go.TypeName != "") || g.isTaggedUnionType(schema) {
+		return nil, nil
+	}
+
+	var u union
+	fieldNames := map[string]struct{}{}
+	kinds := map[jsonschema.PrimitiveType]struct{}{}
+	for i, branch := range branches {
+		resolved := g.resolve(branch)
+		if g.isExternal(resolved) {
+			return nil, fmt.Errorf("branch %d is a type in another package", i)
+		}
+		var typ jsonschema.PrimitiveType
+		switch {
+		case len(resolved.Type) == 1 || (len(resolved.Type) == 2 && isNullable(resolved)):
+			typ = resolved.Type[0]
+			if len(resolved.Type) == 2 && typ == jsonschema.NullType {
+				typ = resolved.Type[1]
+			}
+			u.nullable = u.nullable || isNullable(resolved)
+		case g.isAllOfStructType(resolved):
+			typ = jsonschema.ObjectType
+		default:
+			return nil, fmt.Errorf("branch %d does not have a single type", i)
+		}
+		if typ == jsonschema.NullType {
+			u.nullable = true
+			continue
+		}
+
+		kind := typ
+		if kind == jsonschema.IntegerType {
+			kind = jsonschema.NumberType
+		}
+		if _, ok := kinds[kind]; ok {
+			return nil, fmt.Errorf("multiple branches allow JSON values of type %s", kind)
+		}
+		kinds[kind] = struct{}{}
+
+		fieldType, imports, err := g.expr(branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get type expression for branch %d: %w", i, err)
+		}
+		fieldName := toGoName(string(typ), "")
+		if ident, ok := fieldType.(*ast.Ident); ok && !isBasicType(ident) && ident.Name != anyType.Name {
+			fieldName = ident.Name
+		}
+		if _, ok := fieldNames[fieldName]; ok {
+			fieldName = toGoName(string(typ), "")
+		}
+		fieldNames[fieldName] = struct{}{}
+		switch fieldType.(type) {
+		case *ast.ArrayType, *ast.MapType:
+		default:
+			fieldType = &ast.StarExpr{X: fieldType}
+		}
+		u.branches = append(u.branches, unionBranch{schema: branch, kind: kind, fieldName: fieldName, fieldType: fieldType, imports: imports})
+	}
+	if len(u.branches) < 2 {
+		return nil, errors.New("fewer than 2 branches allow non-null JSON values")
+	}
+	return &u, nil
+}
+
+// emitUnionType emits a Go union type for schema, with a field for each of its anyOf (or oneOf)
+// branches and MarshalJSON and UnmarshalJSON methods that use the field for the kind of JSON value.
+func (g *generator) emitUnionType(schema *jsonschema.Schema, u *union) ([]ast.Decl, []*ast.ImportSpec, error) {
+	goName, err := g.goNameForSchema(schema, g.schemas[schema])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imports := importSpecs("bytes", "encoding/json", "errors", "fmt")
+	fields := make([]*ast.Field, len(u.branches))
+	fieldNames := make([]string, len(u.branches))
+	fieldNamesByFirstByte := map[string]string{}
+	for i, b := range u.branches {
+		imports = append(imports, b.imports...)
+		fields[i] = &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(b.fieldName)},
+			Type:  b.fieldType,
+		}
+		fieldNames[i] = b.fieldName
+		fieldNamesByFirstByte[unionFirstBytes[b.kind]] = b.fieldName
+	}
+	typeDecl := &ast.GenDecl{
+		Doc: docForSchema(schema, goName),
+		Tok: token.TYPE,
+		Specs: []ast.Spec{&ast.TypeSpec{
+			Name: ast.NewIdent(goName),
+			Type: &ast.StructType{Fields: &ast.FieldList{List: fields}},
+		}},
+	}
+
+	// Generate MarshalJSON and UnmarshalJSON methods on the Go union type.
+	templateData := map[string]any{
+		"goName":                goName,
+		"fieldNames":            fieldNames,
+		"fieldNamesByFirstByte": fieldNamesByFirstByte,
+		"nullable":              u.nullable,
+	}
+	marshalJSONDecl, err := parseFuncLitToFuncDecl(executeTemplate(unionTypeMarshalJSONTemplate, templateData))
+	if err != nil {
+		return nil, nil, err
+	}
+	unmarshalJSONDecl, err := parseFuncLitToFuncDecl(executeTemplate(unionTypeUnmarshalJSONTemplate, templateData))
+	if err != nil {
+		return nil, nil, err
+	}
+	makeMethod(marshalJSONDecl, ast.NewIdent(goName), "MarshalJSON")
+	makeMethod(unmarshalJSONDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "UnmarshalJSON")
+
+	decls := []ast.Decl{typeDecl, marshalJSONDecl, unmarshalJSONDecl}
+	if g.opts.EmitValidateMethods {
+		// Only the field for the kind of JSON value is set, so the others' checks don't apply.
+		c := validateCode{g: g, goName: goName, imports: map[string]struct{}{}}
+		c.printf("var n int\n")
+		for _, b := range u.branches {
+			typ, x := b.fieldType, "v."+b.fieldName
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+				if !g.hasValidateMethod(b.schema) {
+					x = "*" + x
+				}
+			}
+			c.printf("if v.%s != nil {\nn++\n", b.fieldName)
+			if err := c.value(b.schema, typ, x, validatePath{}, false); err != nil {
+				return nil, nil, fmt.Errorf("failed to emit validation for union type field %s: %w", b.fieldName, err)
+			}
+			c.printf("}\n")
+		}
+		if u.nullable {
+			c.printf("if n > 1 {\n")
+			c.errorf(validatePath{}, "union type must have at most 1 non-nil field value")
+		} else {
+			c.printf("if n != 1 {\n")
+			c.errorf(validatePath{}, "union type must have exactly 1 non-nil field value")
+		}
+		c.printf("}\n")
+		validateDecl, imports1, err := c.funcDecl(goName)
+		if err != nil {
+			return nil, nil, err
+		}
+		decls = append(decls, validateDecl)
+		imports = append(imports, imports1...)
+	}
+	return decls, imports, nil
+}
+
+// unionFirstBytes is the Go expression for the set of bytes that JSON values of each kind begin
+// with (in a switch case).
+var unionFirstBytes = map[jsonschema.PrimitiveType]string{
+	jsonschema.StringType:  `'"'`,
+	jsonschema.NumberType:  `'-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9'`,
+	jsonschema.BooleanType: `'t', 'f'`,
+	jsonschema.ArrayType:   `'['`,
+	jsonschema.ObjectType:  `'{'`,
+}
+
+var (
+	unionTypeMarshalJSONTemplate = template.Must(template.New("").Parse(`
+func() ([]byte, error) {
+	{{range .fieldNames}}
+	if v.{{.}} != nil {
+		return json.Marshal(v.{{.}})
+	}
+	{{end}}
+	{{- if .nullable}}
+	return []byte("null"), nil
+	{{- else}}
+	return nil, errors.New("union type must have exactly 1 non-nil field value")
+	{{- end}}
+}
+`))
+	unionTypeUnmarshalJSONTemplate = template.Must(template.New("").Parse(`
+func(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("unexpected end of JSON input")
+	}
+	*v = {{.goName}}{}
+	switch data[0] {
+	{{- range $firstBytes, $fieldName := .fieldNamesByFirstByte}}
+	case {{$firstBytes}}:
+		return json.Unmarshal(data, &v.{{$fieldName}}){{end}}
+	{{- if .nullable}}
+	case 'n':
+		return nil
+	{{- end}}
+	}
+	return fmt.Errorf("union type {{.goName}} can't hold the JSON value %s", data)
+}
+`))
+)
@@ -0,0 +1,96 @@
+package compiler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	anyofunion "github.com/sourcegraph/go-jsonschema/compiler/testdata/anyOf-union"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestUnionType_unmarshalReplaces(t *testing.T) {
+	var v anyofunion.A
+	for _, data := range []string{`"s"`, `true`} {
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v.String != nil || v.Boolean == nil || !*v.Boolean {
+		t.Errorf("got %+v, want only Boolean set to true", v)
+	}
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "true" {
+		t.Errorf("got %s, want true", got)
+	}
+}
+
+func TestUnionType_validate(t *testing.T) {
+	i, s := -1, "s"
+	tests := map[string]struct {
+		value   anyofunion.B
+		wantErr string // empty if valid
+	}{
+		"null":            {value: anyofunion.B{}},
+		"other field set": {value: anyofunion.B{Point: &anyofunion.Point{}}},
+		"invalid field":   {value: anyofunion.B{Integer: &i}, wantErr: "must be greater than or equal to 0"},
+		"invalid array":   {value: anyofunion.B{Array: []string{}}, wantErr: "must have at least 1 items"},
+		"2 fields set":    {value: anyofunion.B{Array: []string{s}, Point: &anyofunion.Point{}}, wantErr: "union type must have at most 1 non-nil field value"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.value.Validate()
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+
+	if err := (anyofunion.A{String: &s}).Validate(); err != nil {
+		t.Errorf("got error %v for a valid value", err)
+	}
+	if err, want := (anyofunion.A{}).Validate(), "union type must have exactly 1 non-nil field value"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestEmitUnionTypes_ambiguous(t *testing.T) {
+	tests := map[string]string{
+		`{"anyOf": [{"type": "integer"}, {"type": "number"}]}`:           `anyOf at "properties/u" can't be represented by a Go union type (multiple branches allow JSON values of type number), so it will be represented by any`,
+		`{"oneOf": [{"type": "string"}, {"enum": ["a", 1]}]}`:            `oneOf at "properties/u" can't be represented by a Go union type (branch 1 does not have a single type), so it will be represented by any`,
+		`{"anyOf": [{"type": "string"}, {"type": "null"}]}`:              `anyOf at "properties/u" can't be represented by a Go union type (fewer than 2 branches allow non-null JSON values), so it will be represented by any`,
+		`{"anyOf": [{"type": "string"}, {"type": ["boolean", "null"]}]}`: "",
+	}
+	for u, want := range tests {
+		t.Run(u, func(t *testing.T) {
+			var schema jsonschema.Schema
+			if err := json.Unmarshal([]byte(`{"title": "t", "type": "object", "properties": {"u": `+u+`}}`), &schema); err != nil {
+				t.Fatal(err)
+			}
+			var warnings []string
+			_, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+				EmitUnionTypes: true,
+				Warn:           func(message string) { warnings = append(warnings, message) },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wantWarnings []string
+			if want != "" {
+				wantWarnings = []string{want}
+			}
+			if !reflect.DeepEqual(warnings, wantWarnings) {
+				t.Errorf("got warnings %q, want %q", warnings, wantWarnings)
+			}
+		})
+	}
+}
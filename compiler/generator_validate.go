@@ -0,0 +1,693 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// emitStructValidateMethod returns a Validate method for the Go struct type (named goName) that
+// was emitted for schema.
+func (g *generator) emitStructValidateMethod(schema *jsonschema.Schema, goName string, fields []field) (*ast.FuncDecl, []*ast.ImportSpec, error) {
+	c := validateCode{g: g, goName: goName, imports: map[string]struct{}{}}
+	for _, f := range fields {
+		if f.embedded {
+			if g.hasValidateMethod(f.schema) {
+				if isNilable(f.Type) {
+					c.printf("if v.%s != nil {\n", f.GoName)
+				}
+				c.printf("if err := v.%s.Validate(); err != nil {\n", f.GoName)
+				c.wrapError(validatePath{}, "err")
+				c.printf("}\n")
+				if isNilable(f.Type) {
+					c.printf("}\n")
+				}
+			}
+			continue
+		}
+		path := validatePath{format: escapePercent(f.JSONName)}
+		x := "v." + f.GoName
+		required := f.required
+		if required && isNilable(f.Type) {
+			c.printf("if %s == nil {\n", x)
+			c.errorf(validatePath{}, fmt.Sprintf("missing required property %q", f.JSONName))
+			c.printf("}\n")
+		}
+		for _, prop := range f.schemas {
+			if err := c.value(prop, f.Type, x, path, !required); err != nil {
+				return nil, nil, fmt.Errorf("failed to emit validation for property %q: %w", f.JSONName, err)
+			}
+		}
+	}
+	if hasExtraPropertiesFields(schema) {
+		if err := c.extraPropertiesFields(schema); err != nil {
+			return nil, nil, err
+		}
+	}
+	return c.funcDecl(goName)
+}
+
+// emitTaggedUnionValidateMethod returns a Validate method for the Go union type (named goName)
+// that was emitted for a schema with the !go.taggedUnionType extension.
+func (g *generator) emitTaggedUnionValidateMethod(goName string, fieldNames []string) (*ast.FuncDecl, []*ast.ImportSpec, error) {
+	c := validateCode{g: g, goName: goName, imports: map[string]struct{}{"errors": {}}}
+	c.printf("var n int\n")
+	for _, name := range fieldNames {
+		c.printf("if v.%s != nil {\nn++\n", name)
+		c.printf("if err := v.%s.Validate(); err != nil {\nerrs = append(errs, err)\n}\n}\n", name)
+	}
+	c.printf("if n != 1 {\nerrs = append(errs, errors.New(%q))\n}\n", "tagged union type must have exactly 1 non-nil field value")
+	return c.funcDecl(goName)
+}
+
+// hasValidateMethod reports whether the Go type for schema has a Validate method. A Go struct type
+// with a field named Validate has none.
+func (g *generator) hasValidateMethod(schema *jsonschema.Schema) bool {
+	if !g.opts.EmitValidateMethods {
+		return false
+	}
+	schema = g.resolve(schema)
+	if g.isExternal(schema) {
+		return false
+	}
+	if _, ok := g.enumType(schema); ok {
+		return true
+	}
+	if g.isTaggedUnionType(schema) || g.isUnionType(schema) || g.isTupleType(schema) {
+		return true
+	}
+	if !g.isStructType(schema) {
+		return false
+	}
+	_, conflicts := g.propertyForField(schema, "Validate")
+	return !conflicts
+}
+
+// zeroValueIsInvalid reports whether the Validate method would reject the zero value of the Go
+// type for the property with the given schemas (one for each allOf branch that defines it), which
+// requires the field to be a pointer if the property is optional (so that Validate can tell the
+// zero value from an absent value).
+func (g *generator) zeroValueIsInvalid(schemas ...*jsonschema.Schema) bool {
+	if !g.opts.EmitValidateMethods {
+		return false
+	}
+	for _, schema := range schemas {
+		schema = g.resolve(schema)
+		if g.isExternal(schema) {
+			continue
+		}
+		typ := jsonSchemaType(schema)
+		switch typ {
+		case jsonschema.StringType:
+			if schema.MinLength != nil && *schema.MinLength > 0 {
+				return true
+			}
+			if schema.Pattern != nil {
+				if re, err := regexp.Compile(*schema.Pattern); err == nil && !re.MatchString("") {
+					return true
+				}
+			}
+		case jsonschema.NumberType, jsonschema.IntegerType:
+			if (schema.Minimum != nil && *schema.Minimum > 0) || (schema.ExclusiveMinimum != nil && *schema.ExclusiveMinimum >= 0) ||
+				(schema.Maximum != nil && *schema.Maximum < 0) || (schema.ExclusiveMaximum != nil && *schema.ExclusiveMaximum <= 0) {
+				return true
+			}
+		}
+
+		var values []any
+		if schema.Const != nil {
+			values = []any{*schema.Const}
+		} else {
+			for _, v := range schema.Enum {
+				values = append(values, v)
+			}
+		}
+		zero := goZeroValueLiteral(typ)
+		var allowed, checked bool
+		for _, v := range values {
+			if lit, ok := goLiteral(v, typ); ok {
+				checked = true
+				allowed = allowed || lit == zero
+			}
+		}
+		if checked && !allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve follows $refs from schema to the schema that they (transitively) refer to.
+func (g *generator) resolve(schema *jsonschema.Schema) *jsonschema.Schema {
+	seen := map[*jsonschema.Schema]struct{}{}
+	for schema.Reference != nil {
+		if _, ok := seen[schema]; ok {
+			break
+		}
+		seen[schema] = struct{}{}
+		target, ok := g.resolutions[schema]
+		if !ok {
+			break
+		}
+		schema = target
+	}
+	return schema
+}
+
+// validateCode accumulates the Go source code for the body of a generated Validate method. The
+// code appends each error it finds to the errs variable.
+type validateCode struct {
+	g       *generator
+	goName  string // the Go type whose method is generated
+	buf     strings.Builder
+	imports map[string]struct{}
+	vars    int // number of loop variables declared (so that their names are unique)
+}
+
+// validatePath is a Go format string (and the Go expressions for its arguments) describing the
+// location of a value relative to the method receiver, for use in error messages.
+type validatePath struct {
+	format string
+	args   []string
+}
+
+func (p validatePath) child(format, arg string) validatePath {
+	if p.format != "" {
+		format = p.format + "/" + format
+	}
+	return validatePath{format: format, args: append(append([]string{}, p.args...), arg)}
+}
+
+func (c *validateCode) printf(format string, args ...any) {
+	fmt.Fprintf(&c.buf, format, args...)
+}
+
+// block emits the Go code written by fn inside a block that begins with header, unless fn writes no
+// code.
+func (c *validateCode) block(header string, fn func() error) error {
+	n := c.buf.Len()
+	c.printf("%s {\n", header)
+	m := c.buf.Len()
+	if err := fn(); err != nil {
+		return err
+	}
+	if c.buf.Len() == m {
+		s := c.buf.String()[:n]
+		c.buf.Reset()
+		c.buf.WriteString(s)
+		return nil
+	}
+	c.printf("}\n")
+	return nil
+}
+
+// errorf emits code to record an error with the message for the value at path. The message is
+// not a format string.
+func (c *validateCode) errorf(path validatePath, message string) {
+	if len(path.args) == 0 {
+		if path.format != "" {
+			message = strings.ReplaceAll(path.format, "%%", "%") + ": " + message
+		}
+		c.printf("errs = append(errs, errors.New(%q))\n", message)
+		return
+	}
+	c.imports["fmt"] = struct{}{}
+	format := escapePercent(message)
+	if path.format != "" {
+		format = path.format + ": " + format
+	}
+	c.printf("errs = append(errs, fmt.Errorf(%s))\n", strings.Join(append([]string{strconv.Quote(format)}, path.args...), ", "))
+}
+
+// wrapError emits code to record the error err (a Go expression) for the value at path.
+func (c *validateCode) wrapError(path validatePath, err string) {
+	if path.format == "" {
+		c.printf("errs = append(errs, %s)\n", err)
+		return
+	}
+	c.imports["fmt"] = struct{}{}
+	c.printf("errs = append(errs, fmt.Errorf(%s))\n", strings.Join(append(append([]string{strconv.Quote(path.format + ": %w")}, path.args...), err), ", "))
+}
+
+func (c *validateCode) newVar(prefix string) string {
+	c.vars++
+	return prefix + strconv.Itoa(c.vars)
+}
+
+// value emits code to validate the Go value x (whose type is typ) against schema. If omitempty is
+// true, then the zero value of typ means that the value is absent (and is not validated).
+func (c *validateCode) value(schema *jsonschema.Schema, typ ast.Expr, x string, path validatePath, omitempty bool) error {
+	schema = c.g.resolve(schema)
+	if c.g.isExternal(schema) || schema.IsEmpty || schema.IsNegated {
+		return nil
+	}
+
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		return c.block(fmt.Sprintf("if %s != nil", x), func() error {
+			if c.g.hasValidateMethod(schema) {
+				return c.value(schema, t.X, x, path, false)
+			}
+			return c.value(schema, t.X, "*"+x, path, false)
+		})
+
+	case *ast.ArrayType:
+		if schema.MinItems != nil {
+			cond := fmt.Sprintf("len(%s) < %d", x, *schema.MinItems)
+			if omitempty {
+				cond = fmt.Sprintf("len(%s) != 0 && %s", x, cond)
+			}
+			c.printf("if %s {\n", cond)
+			c.errorf(path, fmt.Sprintf("must have at least %d items", *schema.MinItems))
+			c.printf("}\n")
+		}
+		if schema.MaxItems != nil {
+			c.printf("if len(%s) > %d {\n", x, *schema.MaxItems)
+			c.errorf(path, fmt.Sprintf("must have at most %d items", *schema.MaxItems))
+			c.printf("}\n")
+		}
+		if schema.UniqueItems != nil && *schema.UniqueItems {
+			// Report duplicates only once, no matter how many items are duplicated.
+			c.imports["reflect"] = struct{}{}
+			i, j := c.newVar("i"), c.newVar("j")
+			label := c.newVar("unique")
+			c.printf("%[4]s:\nfor %[1]s := range %[3]s {\nfor %[2]s := %[1]s + 1; %[2]s < len(%[3]s); %[2]s++ {\n", i, j, x, label)
+			c.printf("if reflect.DeepEqual(%[3]s[%[1]s], %[3]s[%[2]s]) {\n", i, j, x)
+			c.errorf(path, "must not contain duplicate items")
+			c.printf("break %s\n}\n}\n}\n", label)
+		}
+		if schema.Items != nil && schema.Items.Schema != nil {
+			i, e := c.newVar("i"), c.newVar("e")
+			return c.block(fmt.Sprintf("for %s, %s := range %s", i, e, x), func() error {
+				return c.value(schema.Items.Schema, t.Elt, e, path.child("%d", i), false)
+			})
+		}
+		return nil
+
+	case *ast.MapType:
+		return c.mapValues(schema, t.Value, x, path, true, true)
+
+	case *ast.Ident:
+		if t.Name == anyType.Name {
+			return nil
+		}
+		typ := jsonSchemaType(schema)
+		if enumType, ok := c.g.enumType(schema); ok {
+			typ = enumType
+		}
+		if zero := goZeroValueLiteral(typ); omitempty && zero != "" {
+			if c.g.zeroValueIsInvalid(schema) {
+				c.g.warnf("the zero value of %s in %s is invalid but can't be told apart from an absent value, so it will not be rejected by the Validate method", strings.ReplaceAll(x, "*", ""), c.goName)
+			}
+			return c.block(fmt.Sprintf("if %s != %s", x, zero), func() error {
+				return c.ident(schema, typ, t, x, path)
+			})
+		}
+		return c.ident(schema, typ, t, x, path)
+
+	case *ast.SelectorExpr:
+		if schema.Format == nil {
+			return nil
+		}
+		if _, _, ok := c.g.formatTypeExpr(schema); ok {
+			if cond, message, ok := formatCheck(*schema.Format, x, omitempty); ok {
+				c.printf("if %s {\n", cond)
+				c.errorf(path, message)
+				c.printf("}\n")
+			}
+		}
+	}
+	return nil
+}
+
+// mapValues emits code to validate the values of the Go map x (whose value type is valueType),
+// which holds properties of an object that is an instance of schema. If patterns is true, each
+// value is validated against the schemas of the patternProperties that its key matches. If
+// additional is true, the values whose keys match none of them are validated against the
+// additionalProperties schema.
+//
+// A value is not validated against a schema whose Go type differs from valueType.
+func (c *validateCode) mapValues(schema *jsonschema.Schema, valueType ast.Expr, x string, path validatePath, patterns, additional bool) error {
+	var patternNames []string
+	if patterns && schema.PatternProperties != nil {
+		patternNames = sortedKeys(*schema.PatternProperties)
+	}
+	patternVar := func(pattern string) (string, error) {
+		patternVar, err := c.g.patternVar(c.goName, pattern)
+		if err != nil {
+			return "", fmt.Errorf("patternProperties %q is not supported by Go's regexp package: %w", pattern, err)
+		}
+		return patternVar, nil
+	}
+
+	k, e := c.newVar("k"), c.newVar("e")
+	return c.block(fmt.Sprintf("for %s, %s := range %s", k, e, x), func() error {
+		// value emits code to validate the map value against schema if cond (which is called only
+		// if there is code to emit) is true.
+		value := func(schema *jsonschema.Schema, cond func() (string, error)) error {
+			if typ, _, err := c.g.expr(schema); err != nil || types.ExprString(typ) != types.ExprString(valueType) {
+				return err
+			}
+			body := validateCode{g: c.g, goName: c.goName, imports: c.imports, vars: c.vars}
+			if err := body.value(schema, valueType, e, path.child("%s", k), false); err != nil {
+				return err
+			}
+			c.vars = body.vars
+			if body.buf.Len() == 0 {
+				return nil
+			}
+			cond1, err := cond()
+			if err != nil {
+				return err
+			}
+			if cond1 == "" {
+				c.buf.WriteString(body.buf.String())
+				return nil
+			}
+			c.printf("if %s {\n%s}\n", cond1, body.buf.String())
+			return nil
+		}
+		for _, pattern := range patternNames {
+			err := value((*schema.PatternProperties)[pattern], func() (string, error) {
+				patternVar, err := patternVar(pattern)
+				return fmt.Sprintf("%s.MatchString(%s)", patternVar, k), err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if additional && schema.AdditionalProperties != nil {
+			return value(schema.AdditionalProperties, func() (string, error) {
+				conds := make([]string, len(patternNames))
+				for i, pattern := range patternNames {
+					patternVar, err := patternVar(pattern)
+					if err != nil {
+						return "", err
+					}
+					conds[i] = fmt.Sprintf("!%s.MatchString(%s)", patternVar, k)
+				}
+				return strings.Join(conds, " && "), nil
+			})
+		}
+		return nil
+	})
+}
+
+// extraPropertiesFields emits code to validate the values in the Go struct fields for the
+// properties that are not among schema's "properties" (see emitStructAdditionalField).
+func (c *validateCode) extraPropertiesFields(schema *jsonschema.Schema) error {
+	astFields, _, _, err := c.g.patternPropertiesFields(schema, c.goName)
+	if err != nil {
+		return err
+	}
+	for _, f := range astFields {
+		x := "v." + f.Names[0].Name
+		if err := c.mapValues(schema, f.Type.(*ast.MapType).Value, x, validatePath{}, true, false); err != nil {
+			return err
+		}
+	}
+	if allowsAdditionalProperties(schema) {
+		valueType, _, err := c.g.expr(schema.AdditionalProperties)
+		if err != nil {
+			return err
+		}
+		// The Additional map holds only the properties that match none of the patternProperties.
+		return c.mapValues(schema, valueType, "v.Additional", validatePath{}, false, true)
+	}
+	return nil
+}
+
+// ident emits code to validate the Go value x whose type is the (builtin or named) Go type goType.
+func (c *validateCode) ident(schema *jsonschema.Schema, typ jsonschema.PrimitiveType, goType *ast.Ident, x string, path validatePath) error {
+	if c.g.hasValidateMethod(schema) {
+		return c.block(fmt.Sprintf("if err := %s.Validate(); err != nil", x), func() error {
+			c.wrapError(path, "err")
+			return nil
+		})
+	}
+	return c.scalar(schema, typ, goType, x, path)
+}
+
+// scalar emits code to validate the Go value x of a builtin type (or a named type with a builtin
+// underlying type).
+func (c *validateCode) scalar(schema *jsonschema.Schema, typ jsonschema.PrimitiveType, goType *ast.Ident, x string, path validatePath) error {
+	switch typ {
+	case jsonschema.StringType:
+		if goType.Name != "string" {
+			x = "string(" + x + ")"
+		}
+		if schema.MinLength != nil {
+			c.imports["unicode/utf8"] = struct{}{}
+			c.printf("if utf8.RuneCountInString(%s) < %d {\n", x, *schema.MinLength)
+			c.errorf(path, fmt.Sprintf("must be at least %d characters long", *schema.MinLength))
+			c.printf("}\n")
+		}
+		if schema.MaxLength != nil {
+			c.imports["unicode/utf8"] = struct{}{}
+			c.printf("if utf8.RuneCountInString(%s) > %d {\n", x, *schema.MaxLength)
+			c.errorf(path, fmt.Sprintf("must be at most %d characters long", *schema.MaxLength))
+			c.printf("}\n")
+		}
+		if schema.Pattern != nil {
+			if pattern, err := c.g.patternVar(c.goName, *schema.Pattern); err != nil {
+				c.g.warnf("pattern %q in %s is not supported by Go's regexp package (%s), so it will not be validated", *schema.Pattern, c.goName, err)
+			} else {
+				c.printf("if !%s.MatchString(%s) {\n", pattern, x)
+				c.errorf(path, fmt.Sprintf("must match the pattern %q", *schema.Pattern))
+				c.printf("}\n")
+			}
+		}
+
+	case jsonschema.NumberType, jsonschema.IntegerType:
+		if intType, ok := lookupGoIntegerType(goType.Name); ok {
+			c.integerBounds(schema, intType, x, path)
+			break
+		}
+		if goType.Name != "float64" {
+			x = "float64(" + x + ")"
+		}
+		bounds := []struct {
+			limit   *float64
+			op, msg string
+		}{
+			{schema.Minimum, "<", "greater than or equal to"},
+			{schema.ExclusiveMinimum, "<=", "greater than"},
+			{schema.Maximum, ">", "less than or equal to"},
+			{schema.ExclusiveMaximum, ">=", "less than"},
+		}
+		for _, b := range bounds {
+			if b.limit != nil {
+				c.printf("if %s %s %s {\n", x, b.op, formatFloat(*b.limit))
+				c.errorf(path, fmt.Sprintf("must be %s %s", b.msg, formatFloat(*b.limit)))
+				c.printf("}\n")
+			}
+		}
+		if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+			c.floatMultipleOf(*schema.MultipleOf, x, path)
+		}
+	}
+
+	// Check enum and const values (of the same type as the Go value).
+	var values []any
+	if schema.Const != nil {
+		values = []any{*schema.Const}
+	} else {
+		for _, v := range schema.Enum {
+			values = append(values, v)
+		}
+	}
+	var conds, literals []string
+	for _, v := range values {
+		lit, ok := goLiteral(v, typ)
+		if !ok {
+			continue
+		}
+		conds = append(conds, fmt.Sprintf("%s != %s", x, lit))
+		literals = append(literals, lit)
+	}
+	if len(conds) > 0 {
+		c.printf("if %s {\n", strings.Join(conds, " && "))
+		if schema.Const != nil {
+			c.errorf(path, "must be equal to "+literals[0])
+		} else {
+			c.errorf(path, "must be one of "+strings.Join(literals, ", "))
+		}
+		c.printf("}\n")
+	}
+	return nil
+}
+
+// funcDecl returns the Validate method (with receiver type goName) whose body is the code
+// accumulated so far.
+func (c *validateCode) funcDecl(goName string) (*ast.FuncDecl, []*ast.ImportSpec, error) {
+	var src string
+	if c.buf.Len() == 0 {
+		src = "func() error {\nreturn nil\n}"
+	} else {
+		c.imports["errors"] = struct{}{}
+		src = "func() error {\nvar errs []error\n" + c.buf.String() + "return errors.Join(errs...)\n}"
+	}
+	decl, err := parseFuncLitToFuncDecl(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	makeMethod(decl, ast.NewIdent(goName), "Validate")
+
+	paths := make([]string, 0, len(c.imports))
+	for path := range c.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return decl, importSpecs(paths...), nil
+}
+
+// jsonSchemaType returns the single non-null primitive type of schema, or
+// jsonschema.UnspecifiedType.
+func jsonSchemaType(schema *jsonschema.Schema) jsonschema.PrimitiveType {
+	for _, typ := range schema.Type {
+		if typ != jsonschema.NullType {
+			return typ
+		}
+	}
+	return jsonschema.UnspecifiedType
+}
+
+// integerBounds emits code to check the bounds (and multipleOf) of the schema for the Go value x of
+// the Go integer type t. The checks compare integers, because converting x to float64 is inexact
+// above 2^53.
+func (c *validateCode) integerBounds(schema *jsonschema.Schema, t goIntegerTypeInfo, x string, path validatePath) {
+	xFloat := "float64(" + x + ")"
+	// The size of int and uint depends on the platform, so convert them to the 64-bit types (whose
+	// range is assumed for them).
+	switch t.name {
+	case "int":
+		x = "int64(" + x + ")"
+	case "uint":
+		x = "uint64(" + x + ")"
+	}
+
+	// For an integer x, x < 1.5 if and only if x < 2 (and so on), so round each limit to an integer.
+	bounds := []struct {
+		limit   *float64
+		op, msg string
+		round   func(float64) float64
+		passes  func(limit float64) bool // whether all values of the Go type satisfy the bound
+	}{
+		{schema.Minimum, "<", "greater than or equal to", math.Ceil, func(limit float64) bool { return limit <= t.min }},
+		{schema.ExclusiveMinimum, "<=", "greater than", math.Floor, func(limit float64) bool { return limit < t.min }},
+		{schema.Maximum, ">", "less than or equal to", math.Floor, func(limit float64) bool { return limit >= t.max }},
+		{schema.ExclusiveMaximum, ">=", "less than", math.Ceil, func(limit float64) bool { return limit > t.max }},
+	}
+	for _, b := range bounds {
+		if b.limit == nil {
+			continue
+		}
+		limit := b.round(*b.limit)
+		if b.passes(limit) {
+			continue
+		}
+		message := fmt.Sprintf("must be %s %s", b.msg, formatNumber(*b.limit))
+		if !t.contains(limit) {
+			// No value of the Go type satisfies the bound (and the limit is not a valid constant of
+			// the Go type).
+			c.errorf(path, message)
+			continue
+		}
+		c.printf("if %s %s %s {\n", x, b.op, t.format(limit))
+		c.errorf(path, message)
+		c.printf("}\n")
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+		if m := math.Abs(*schema.MultipleOf); m == math.Trunc(m) && t.contains(m) {
+			c.printf("if %s%%%s != 0 {\n", x, t.format(m))
+			c.errorf(path, fmt.Sprintf("must be a multiple of %s", formatNumber(*schema.MultipleOf)))
+			c.printf("}\n")
+		} else {
+			c.floatMultipleOf(*schema.MultipleOf, xFloat, path)
+		}
+	}
+}
+
+// floatMultipleOf emits code to check that the float64 value x is a multiple of m.
+func (c *validateCode) floatMultipleOf(m float64, x string, path validatePath) {
+	// Allow for floating-point error (for example, 0.0075 / 0.0001 is not exactly 75).
+	c.imports["math"] = struct{}{}
+	q := c.newVar("q")
+	c.printf("if %[1]s := %[2]s / %[3]s; math.Abs(%[1]s-math.Round(%[1]s)) > 1e-9 {\n", q, x, formatFloat(m))
+	c.errorf(path, fmt.Sprintf("must be a multiple of %s", formatFloat(m)))
+	c.printf("}\n")
+}
+
+// goZeroValueLiteral returns the Go literal for the zero value of the builtin Go type that
+// represents typ.
+func goZeroValueLiteral(typ jsonschema.PrimitiveType) string {
+	switch typ {
+	case jsonschema.StringType:
+		return `""`
+	case jsonschema.NumberType, jsonschema.IntegerType:
+		return "0"
+	case jsonschema.BooleanType:
+		return "false"
+	}
+	return ""
+}
+
+// goLiteral returns the Go literal for the JSON value v, if v is a value of the primitive type
+// typ.
+func goLiteral(v any, typ jsonschema.PrimitiveType) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		if typ == jsonschema.StringType {
+			return strconv.Quote(v), true
+		}
+	case float64:
+		if typ == jsonschema.NumberType || (typ == jsonschema.IntegerType && v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxUint64) {
+			return formatNumber(v), true
+		}
+	case bool:
+		if typ == jsonschema.BooleanType {
+			return strconv.FormatBool(v), true
+		}
+	}
+	return "", false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// formatNumber is like formatFloat, except that it formats integers in the range of int64 or
+// uint64 without an exponent.
+func formatNumber(f float64) string {
+	if f == math.Trunc(f) {
+		switch {
+		case f >= math.MinInt64 && f < math.MaxInt64:
+			return strconv.FormatInt(int64(f), 10)
+		case f >= 0 && f < math.MaxUint64:
+			return strconv.FormatUint(uint64(f), 10)
+		}
+	}
+	return formatFloat(f)
+}
+
+func escapePercent(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// isNilable reports whether the Go type can be nil.
+func isNilable(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.InterfaceType:
+		return true
+	case *ast.Ident:
+		return t.Name == anyType.Name
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package compiler
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"testing"
+
+	testdata_integer "github.com/sourcegraph/go-jsonschema/compiler/testdata/integer"
+	testdata_validate "github.com/sourcegraph/go-jsonschema/compiler/testdata/validate"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// TestValidateMethods depends on the generated ./testdata/validate/want.go file, which you can
+// overwrite with the latest generated code by running `go test -test.write-want`.
+func TestValidateMethods(t *testing.T) {
+	intptr := func(v int) *int { return &v }
+	strptr := func(v string) *string { return &v }
+	floatptr := func(v float64) *float64 { return &v }
+	valid := testdata_validate.Validate{Name: "abc", Tags: []string{"aa", "bb"}}
+
+	tests := map[string]struct {
+		value   testdata_validate.Validate
+		wantErr string // empty if valid
+	}{
+		"valid": {
+			value: valid,
+		},
+		"valid with optional fields": {
+			value: testdata_validate.Validate{
+				Name:     "abc",
+				Tags:     []string{"aa"},
+				Protocol: strptr("https"),
+				Port:     intptr(443),
+				Ratio:    floatptr(1.25),
+				Limit:    intptr(10),
+				Children: []*testdata_validate.Child{{Id: "abc"}},
+				Shapes:   []testdata_validate.Shapes{{Circle: &testdata_validate.Circle{Kind: "circle", Radius: 1}}},
+			},
+		},
+		"invalid string": {
+			value:   testdata_validate.Validate{Name: "ABC", Tags: []string{"aa"}},
+			wantErr: `name: must match the pattern "^[a-z]+$"`,
+		},
+		"invalid enum": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Protocol: strptr("ftp")},
+			wantErr: `protocol: must be one of "http", "https"`,
+		},
+		"invalid number": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Ratio: floatptr(0.3)},
+			wantErr: "ratio: must be a multiple of 0.25",
+		},
+		"invalid zero values": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Protocol: strptr(""), Port: intptr(0), Ratio: floatptr(0)},
+			wantErr: "port: must be greater than or equal to 1\nprotocol: must be one of \"http\", \"https\"\nratio: must be greater than 0",
+		},
+		"invalid pointer": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Limit: intptr(11)},
+			wantErr: "limit: must be less than or equal to 10",
+		},
+		"missing required array": {
+			value:   testdata_validate.Validate{Name: "abc"},
+			wantErr: "missing required property \"tags\"\ntags: must have at least 1 items",
+		},
+		"invalid array items": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa", "b", "aa", "aa"}},
+			wantErr: "tags: must not contain duplicate items\ntags/1: must be at least 2 characters long",
+		},
+		"invalid map value": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Quotas: map[string]int{"a": -1}},
+			wantErr: "quotas/a: must be greater than or equal to 0",
+		},
+		"invalid patternProperties value": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Child: &testdata_validate.Child{Id: "abc", PatternProperties: map[string]string{"x-a": ""}}},
+			wantErr: "child: x-a: must be at least 1 characters long",
+		},
+		"invalid additionalProperties value": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Child: &testdata_validate.Child{Id: "abc", Additional: map[string]int{"b": 6}}},
+			wantErr: "child: b: must be less than or equal to 5",
+		},
+		"invalid nested": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Children: []*testdata_validate.Child{nil, {Id: "a"}}},
+			wantErr: "children/1: id: must be at least 3 characters long",
+		},
+		"invalid tagged union": {
+			value:   testdata_validate.Validate{Name: "abc", Tags: []string{"aa"}, Shapes: []testdata_validate.Shapes{{Square: &testdata_validate.Square{Kind: "circle"}}}},
+			wantErr: `shapes/0: kind: must be equal to "square"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.value.Validate()
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateMethods_integers depends on the generated ./testdata/integer/want.go file.
+func TestValidateMethods_integers(t *testing.T) {
+	uint8ptr := func(v uint8) *uint8 { return &v }
+	tests := map[string]struct {
+		value   testdata_integer.Packet
+		wantErr string // empty if valid
+	}{
+		"valid": {
+			value: testdata_integer.Packet{Nonce: 1 << 53, Level: uint8ptr(1), Timestamp: -(1<<53 - 1)},
+		},
+		// 2^53+1 is not exactly representable as a float64 (it rounds to 2^53).
+		"above 2^53": {
+			value:   testdata_integer.Packet{Nonce: 1<<53 + 1},
+			wantErr: "nonce: must be less than or equal to 9007199254740992\nnonce: must be a multiple of 2",
+		},
+		"below -2^53": {
+			value:   testdata_integer.Packet{Timestamp: -(1 << 53)},
+			wantErr: "timestamp: must be greater than or equal to -9007199254740991",
+		},
+		"fractional bound": {
+			value:   testdata_integer.Packet{Level: uint8ptr(0)},
+			wantErr: "level: must be greater than 0.5",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.value.Validate()
+			if (err == nil) != (test.wantErr == "") || (err != nil && err.Error() != test.wantErr) {
+				t.Errorf("got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMethods_unsupportedPattern(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{
+  "title": "t",
+  "type": "object",
+  "properties": {
+    "p": {"type": "string", "pattern": "^(?!x)"}
+  }
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	decls, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+		EmitValidateMethods: true,
+		Warn:                func(message string) { warnings = append(warnings, message) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"pattern \"^(?!x)\" in T is not supported by Go's regexp package (error parsing regexp: invalid or unsupported Perl syntax: `(?!`), so it will not be validated"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+	for _, decl := range decls {
+		if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.VAR {
+			t.Errorf("got a variable declaration for the unsupported pattern")
+		}
+	}
+}
+
+func TestValidateMethods_methodConflict(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{
+  "title": "t",
+  "type": "object",
+  "properties": {
+    "validate": {"type": "boolean"}
+  }
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	decls, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, Options{
+		EmitValidateMethods: true,
+		Warn:                func(message string) { warnings = append(warnings, message) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`T has a property "validate", which conflicts with the Validate method, so no Validate method will be emitted for it`}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+	for _, decl := range decls {
+		if d, ok := decl.(*ast.FuncDecl); ok && d.Name.Name == "Validate" {
+			t.Errorf("got a Validate method")
+		}
+	}
+}
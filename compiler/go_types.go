@@ -0,0 +1,69 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// boundGoTypes returns the existing Go types that represent schemas (instead of emitted Go types),
+// as given by Options.GoTypes and the !go.import and !go.type extensions.
+func boundGoTypes(locationsByRoot schemaLocationsByRoot, opts Options) (map[*jsonschema.Schema]externalType, error) {
+	byLocation := make(map[string]externalType, len(opts.GoTypes))
+	for location, goType := range opts.GoTypes {
+		byLocation[normalizeSchemaURI(location)] = parseGoType(goType)
+	}
+
+	goTypes := map[*jsonschema.Schema]externalType{}
+	for root, locations := range locationsByRoot {
+		for schema, location := range locations {
+			if typ, ok := byLocation[schemaURI(root, location)]; ok {
+				goTypes[schema] = typ
+			} else if typ, ok := byLocation[schemaIDURI(schema, location)]; ok {
+				goTypes[schema] = typ
+			} else if schema.Go != nil && (schema.Go.Import != "" || schema.Go.Type != "") {
+				typ := externalType{importPath: schema.Go.Import, name: schema.Go.Type}
+				if err := typ.check(); err != nil {
+					return nil, fmt.Errorf("invalid !go.import and !go.type at %q in root schema %s: %w", jsonschema.EncodeReferenceTokens(location.rel), describeRoot(root), err)
+				}
+				goTypes[schema] = typ
+			}
+		}
+	}
+	return goTypes, nil
+}
+
+// schemaIDURI returns the schema's "$id" (resolved against its ancestors' base URIs) in the form
+// used by schemaURI, or "" if it has none (or if it is a plain-name fragment, such as "#foo").
+func schemaIDURI(schema *jsonschema.Schema, location schemaLocation) string {
+	if schema.ID == nil || location.id == nil || location.id.ReferenceTokens != nil || location.id.Base.Fragment != "" {
+		return ""
+	}
+	return normalizeSchemaURI(location.id.Base.String())
+}
+
+// parseGoType parses a Go type in the form used by Options.GoTypes, such as "time.Duration",
+// "example.com/project/repo.Repo", or "Duration".
+func parseGoType(goType string) externalType {
+	if i := strings.LastIndex(goType, "."); i > strings.LastIndex(goType, "/") {
+		return externalType{importPath: goType[:i], name: goType[i+1:]}
+	}
+	return externalType{name: goType}
+}
+
+// check reports an error if typ is not a valid reference to a Go named type.
+func (typ externalType) check() error {
+	if typ.name == "" {
+		return errors.New("no type name")
+	}
+	if !token.IsIdentifier(typ.name) {
+		return fmt.Errorf("%q is not a Go identifier", typ.name)
+	}
+	if typ.importPath != "" && !token.IsIdentifier(packageNameForImportPath(typ.importPath)) {
+		return fmt.Errorf("the last element of import path %q is not a Go package name", typ.importPath)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package compiler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestParseGoType(t *testing.T) {
+	tests := map[string]externalType{
+		"Duration":                       {name: "Duration"},
+		"time.Duration":                  {importPath: "time", name: "Duration"},
+		"example.com/project/repo.Repo":  {importPath: "example.com/project/repo", name: "Repo"},
+		"example.com/project/users/v2.U": {importPath: "example.com/project/users/v2", name: "U"},
+		"example.com/project":            {name: "example.com/project"}, // invalid (no type name)
+	}
+	for goType, want := range tests {
+		if got := parseGoType(goType); got != want {
+			t.Errorf("%q: got %+v, want %+v", goType, got, want)
+		}
+	}
+}
+
+func TestCompile_invalidGoExtension(t *testing.T) {
+	tests := map[string]struct {
+		goExtension string
+		wantErr     string
+	}{
+		"no type":      {goExtension: `{"import": "time"}`, wantErr: "no type name"},
+		"invalid type": {goExtension: `{"type": "time.Duration"}`, wantErr: `"time.Duration" is not a Go identifier`},
+		"invalid import": {
+			goExtension: `{"import": "example.com/go-time", "type": "Duration"}`,
+			wantErr:     `the last element of import path "example.com/go-time" is not a Go package name`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema jsonschema.Schema
+			if err := json.Unmarshal([]byte(`{"type": "object", "properties": {"p": {"type": "integer", "!go": `+test.goExtension+`}}}`), &schema); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := Compile([]*jsonschema.Schema{&schema})
+			if err == nil || !strings.HasSuffix(err.Error(), test.wantErr) {
+				t.Errorf("got error %v, want it to end with %q", err, test.wantErr)
+			}
+		})
+	}
+}
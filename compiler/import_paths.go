@@ -0,0 +1,150 @@
+package compiler
+
+import (
+	"go/ast"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// importPathForRoot returns the import path of the Go package that holds the Go types for the root
+// schema (see Options.ImportPaths), or "" if they are emitted in the current package.
+func (opts Options) importPathForRoot(root *jsonschema.Schema) string {
+	if root.BaseURI() == "" {
+		return ""
+	}
+	return opts.importPathForID(strings.TrimSuffix(root.BaseURI(), "#"))
+}
+
+// importPathForID returns the import path for the longest prefix of id in Options.ImportPaths, or
+// "" if there is none.
+func (opts Options) importPathForID(id string) string {
+	var longest, importPath string
+	for prefix, p := range opts.ImportPaths {
+		if strings.HasPrefix(id, prefix) && len(prefix) >= len(longest) {
+			longest, importPath = prefix, p
+		}
+	}
+	return importPath
+}
+
+// importPathsLoader is a Loader that loads documents using loader, except that a document whose URI
+// has a prefix in Options.ImportPaths and that fails to load is replaced by an empty placeholder
+// schema (which is added to unavailable). Such documents are not needed, because their Go types are
+// in another package (see Options.ImportPaths).
+type importPathsLoader struct {
+	loader      jsonschema.Loader
+	opts        Options
+	unavailable map[*jsonschema.Schema]struct{}
+}
+
+// Load implements jsonschema.Loader.
+func (l *importPathsLoader) Load(uri *url.URL) (*jsonschema.Schema, error) {
+	schema, err := l.loader.Load(uri)
+	if err != nil && l.opts.importPathForID(uri.String()) != "" {
+		schema, err = &jsonschema.Schema{RetrievalURI: uri.String()}, nil
+		l.unavailable[schema] = struct{}{}
+	}
+	return schema, err
+}
+
+// jsonschemaImportPath is the import path of the package whose Schema type represents the
+// meta-schema.
+const jsonschemaImportPath = "github.com/sourcegraph/go-jsonschema/jsonschema"
+
+// An externalType is a Go named type in another package that represents a schema that is not among
+// the root schemas (and their subschemas) that are being compiled.
+type externalType struct {
+	importPath string
+	name       string
+}
+
+// externalTypeForRef returns the Go type that represents the schema that ref (a dereferenced $ref
+// URI) refers to, if the document is not among the root schemas and its Go types are in another
+// package: a package in Options.ImportPaths, or package jsonschema (for the meta-schema, which is
+// represented by jsonschema.Schema).
+//
+// The name of the Go type is given by Options.TypeNames or is derived from ref (see
+// externalTypeName).
+func (opts Options) externalTypeForRef(ref *url.URL) (externalType, bool) {
+	doc := *ref
+	doc.Fragment, doc.RawFragment = "", ""
+	importPath := opts.importPathForID(doc.String())
+	if importPath == "" {
+		if isRefToMetaSchema(ref) {
+			return externalType{importPath: jsonschemaImportPath, name: "Schema"}, true
+		}
+		return externalType{}, false
+	}
+
+	uri := normalizeSchemaURI(ref.String())
+	for location, name := range opts.TypeNames {
+		if normalizeSchemaURI(location) == uri {
+			return externalType{importPath: importPath, name: name}, true
+		}
+	}
+	return externalType{importPath: importPath, name: opts.TypeNamePrefix + externalTypeName(ref)}, true
+}
+
+// isExternal reports whether schema is represented by a Go type that is not emitted: a type in
+// another package that a $ref refers to (see resolveReferences), or an existing Go type that the
+// schema is bound to (see boundGoTypes).
+func (g *generator) isExternal(schema *jsonschema.Schema) bool {
+	_, ok := g.externalTypes[schema]
+	return ok
+}
+
+// externalTypeName returns the name of the Go type for the schema that ref refers to, derived from
+// ref alone (because the schema is not available): the last reference token of its JSON Pointer
+// fragment that is not a keyword or array index (such as "foo" for "#/definitions/foo"), or its
+// plain-name fragment (for an "$anchor"), or else the last element of its path without extensions
+// (such as "repo" for "repo.schema.json").
+func externalTypeName(ref *url.URL) string {
+	if strings.HasPrefix(ref.Fragment, "/") {
+		if ptr, err := jsonschema.ParsePointer(ref.Fragment); err == nil {
+			for i := len(ptr) - 1; i >= 0; i-- {
+				if _, isKeyword := subschemaKeywords[ptr[i]]; !isKeyword && strings.Trim(ptr[i], "0123456789") != "" {
+					return toGoName(ptr[i], "Schema_")
+				}
+			}
+		}
+	} else if ref.Fragment != "" {
+		return toGoName(ref.Fragment, "Schema_")
+	}
+	name, _, _ := strings.Cut(path.Base(ref.Path), ".")
+	return toGoName(name, "Schema_")
+}
+
+// subschemaKeywords are the JSON Schema keywords whose values are (or contain) subschemas.
+var subschemaKeywords = map[string]struct{}{
+	"$defs": {}, "additionalItems": {}, "additionalProperties": {}, "allOf": {}, "anyOf": {},
+	"contains": {}, "definitions": {}, "dependencies": {}, "dependentSchemas": {}, "else": {},
+	"if": {}, "items": {}, "not": {}, "oneOf": {}, "patternProperties": {}, "prefixItems": {},
+	"properties": {}, "propertyNames": {}, "then": {}, "unevaluatedItems": {},
+	"unevaluatedProperties": {},
+}
+
+// packageNameForImportPath returns the name of the Go package with the import path, assuming that
+// it is the last element of the path (or the one before it, if the last is a major version suffix
+// such as "v2").
+func packageNameForImportPath(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
+
+// qualifiedTypeExpr returns the Go expression AST node that refers to the Go named type in the
+// package with the import path, and the import of the package.
+func qualifiedTypeExpr(importPath, goName string) (ast.Expr, []*ast.ImportSpec) {
+	name := packageNameForImportPath(importPath)
+	imports := importSpecs(importPath)
+	if name != path.Base(importPath) {
+		imports[0].Name = ast.NewIdent(name)
+	}
+	return &ast.SelectorExpr{X: ast.NewIdent(name), Sel: ast.NewIdent(goName)}, imports
+}
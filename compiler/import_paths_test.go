@@ -0,0 +1,108 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// TestCompile_importPaths checks that the Go types referred to in the import-paths test case (in
+// other packages) have the same names as the types emitted when the other package is compiled
+// separately.
+func TestCompile_importPaths(t *testing.T) {
+	loader := jsonschema.FSLoader{FS: os.DirFS(filepath.Join("testdata", "import-paths", externalDir)), Base: "https://example.com/external/"}
+	var schemas []*jsonschema.Schema
+	for _, uri := range []string{"https://example.com/external/shared/repo.json", "https://example.com/external/shared/common.json"} {
+		schema, err := jsonschema.Load(loader, uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schemas = append(schemas, schema)
+	}
+	decls, _, err := CompileWithOptions(schemas, Options{
+		EmitEnumTypes: true,
+		TypeNames:     map[string]string{"https://example.com/external/shared/repo.json#": "Repository"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, decl := range decls {
+		if d, ok := decl.(*ast.GenDecl); ok {
+			if spec, ok := d.Specs[0].(*ast.TypeSpec); ok {
+				names = append(names, spec.Name.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	if want := []string{"Owner", "Repository", "Tag", "Visibility"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got type names %q, want %q", names, want)
+	}
+}
+
+// TestCompile_importPathsUnavailable checks that documents in other packages that fail to load are
+// treated as if there were no Loader.
+func TestCompile_importPathsUnavailable(t *testing.T) {
+	dir := filepath.Join("testdata", "import-paths-unloaded")
+	var schema jsonschema.Schema
+	var opts Options
+	for file, v := range map[string]any{"schema.json": &schema, optionsFile: &opts} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	compile := func(opts Options) string {
+		decls, imports, err := CompileWithOptions([]*jsonschema.Schema{&schema}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := format.Node(&buf, token.NewFileSet(), &ast.File{Name: ast.NewIdent("p"), Imports: imports, Decls: decls}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	want := compile(opts)
+	opts.Loader = jsonschema.FSLoader{FS: fstest.MapFS{}, Base: "https://example.com/"}
+	if got := compile(opts); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// Documents that are not in other packages must still be loaded.
+	ref := "other.json"
+	schema.Properties = &map[string]*jsonschema.Schema{"x": {Reference: &ref}}
+	if _, _, err := CompileWithOptions([]*jsonschema.Schema{&schema}, opts); err == nil {
+		t.Error("got no error for a $ref to a document that fails to load")
+	}
+}
+
+func TestPackageNameForImportPath(t *testing.T) {
+	tests := map[string]string{
+		"p":                     "p",
+		"example.com/a/b":       "b",
+		"example.com/a/v2":      "a",
+		"example.com/a/v":       "v",
+		"example.com/a/vendor":  "vendor",
+		"example.com/a/go-json": "go-json",
+	}
+	for importPath, want := range tests {
+		if got := packageNameForImportPath(importPath); got != want {
+			t.Errorf("%q: got %q, want %q", importPath, got, want)
+		}
+	}
+}
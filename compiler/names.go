@@ -2,16 +2,44 @@ package compiler
 
 import (
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
+// goNameForSchema returns the name of the Go type emitted for schema: the name assigned to it by
+// assignGoNames, or else its base name (see baseGoNameForSchema) with Options.TypeNamePrefix
+// prepended.
+func (g *generator) goNameForSchema(schema *jsonschema.Schema, location schemaLocation) (string, error) {
+	if name, ok := g.typeNames[schema]; ok {
+		return name, nil
+	}
+	name, err := goNameForSchema(schema, location)
+	if err != nil {
+		return "", err
+	}
+	return g.opts.TypeNamePrefix + name, nil
+}
+
+// goNameForSchema returns the name of the Go type for schema, derived from its title or else from
+// its location.
 func goNameForSchema(schema *jsonschema.Schema, location schemaLocation) (string, error) {
-	var name string
+	name, _, _, err := baseGoNameForSchema(schema, location)
+	return name, err
+}
+
+// baseGoNameForSchema returns the name of the Go type for schema (without Options.TypeNamePrefix),
+// derived from its title or else from the nearest reference token in its location that is defined
+// by the schema author. It also returns the reference tokens (in location) that precede and follow
+// the one that the name was derived from, which qualifyGoName uses to disambiguate the name.
+func baseGoNameForSchema(schema *jsonschema.Schema, location schemaLocation) (name string, ancestors, descendants []jsonschema.ReferenceToken, err error) {
 	if schema.Title != nil {
 		name = *schema.Title
+		ancestors = location.rel
 	}
 	if name == "" {
 		// Take the nearest ancestor reference token that is defined by the schema author and is not
@@ -20,15 +48,171 @@ func goNameForSchema(schema *jsonschema.Schema, location schemaLocation) (string
 			refToken := location.rel[i]
 			if refToken.Name != "" && !refToken.Keyword {
 				name = refToken.Name
+				ancestors, descendants = location.rel[:i], location.rel[i+1:]
 				break
 			}
 		}
 	}
 	if name == "" {
-		return "", fmt.Errorf("schema at %q has no viable name", jsonschema.EncodeReferenceTokens(location.rel))
+		return "", nil, nil, fmt.Errorf("schema at %q has no viable name", jsonschema.EncodeReferenceTokens(location.rel))
+	}
+
+	return toGoName(name, "Schema_"), ancestors, descendants, nil
+}
+
+// goTypeName is the (possibly parent-qualified) name of the Go type emitted for a schema, during
+// assignGoNames.
+type goTypeName struct {
+	root     *jsonschema.Schema
+	location schemaLocation
+	name     string // without Options.TypeNamePrefix
+	override bool   // whether the name is given by Options.TypeNames or the schema's !go.typeName
+	pkg      string // the import path of the Go package that holds the type ("" for the current one)
+
+	// The reference tokens that may still qualify the name.
+	ancestors, descendants []jsonschema.ReferenceToken
+}
+
+// assignGoNames returns the names of the Go named types emitted for the schemas in all roots. The
+// names are unique: if the base names (see baseGoNameForSchema) of 2 or more schemas are the same,
+// each of them is qualified by the names of its ancestors (e.g., "SettingsItems" for the "items"
+// property of the "settings" property) and then by the array indexes of its unnamed location (e.g.,
+// "U0" and "U1" for the untitled oneOf branches of the "u" property) until they differ. The name given
+// by Options.TypeNames or a struct schema's !go.typeName is used as-is. Only the names of types in
+// the same Go package (see Options.ImportPaths) must be unique.
+//
+// It returns an error that lists the locations of the schemas whose names conflict if they can't be
+// disambiguated.
+func assignGoNames(locationsByRoot schemaLocationsByRoot, resolutions map[*jsonschema.Schema]*jsonschema.Schema, externalTypes map[*jsonschema.Schema]externalType, opts Options) (map[*jsonschema.Schema]string, error) {
+	overrides := make(map[string]string, len(opts.TypeNames))
+	for location, name := range opts.TypeNames {
+		overrides[normalizeSchemaURI(location)] = name
+	}
+
+	names := map[*jsonschema.Schema]*goTypeName{}
+	for root, schemas := range locationsByRoot {
+		g := generator{schemas: schemas, resolutions: resolutions, externalTypes: externalTypes, schemaLocator: locationsByRoot, opts: opts}
+		g.markMergedAllOfBranches()
+		for schema, location := range schemas {
+			if !g.emitsNamedType(schema) {
+				continue
+			}
+			n := &goTypeName{root: root, location: location, pkg: opts.importPathForRoot(root)}
+			if name, ok := overrides[schemaURI(root, location)]; ok {
+				n.name, n.override = name, true
+			} else if schema.Go != nil && schema.Go.TypeName != "" {
+				n.name, n.override = schema.Go.TypeName, true
+			} else {
+				var err error
+				n.name, n.ancestors, n.descendants, err = baseGoNameForSchema(schema, location)
+				if err != nil {
+					return nil, err
+				}
+			}
+			names[schema] = n
+		}
+	}
+
+	for {
+		bySchemaName := map[string][]*goTypeName{} // key is the package and name
+		for _, n := range names {
+			key := n.pkg + " " + n.name
+			bySchemaName[key] = append(bySchemaName[key], n)
+		}
+		qualified := false
+		for _, key := range sortedKeys(bySchemaName) {
+			conflicting := bySchemaName[key]
+			if len(conflicting) == 1 {
+				continue
+			}
+			progress := false
+			for _, n := range conflicting {
+				if !n.override && qualifyGoName(n) {
+					progress = true
+				}
+			}
+			if !progress {
+				return nil, goNameConflictError(conflicting[0].name, conflicting)
+			}
+			qualified = true
+		}
+		if !qualified {
+			break
+		}
+	}
+
+	typeNames := make(map[*jsonschema.Schema]string, len(names))
+	for schema, n := range names {
+		if n.override {
+			typeNames[schema] = n.name
+		} else {
+			typeNames[schema] = opts.TypeNamePrefix + n.name
+		}
 	}
+	return typeNames, nil
+}
 
-	return toGoName(name, "Schema_"), nil
+// qualifyGoName prepends the name of the nearest remaining ancestor (that is defined by the schema
+// author) to n's name, or if there is none, appends the next remaining array index in n's location.
+// It reports whether it changed the name.
+func qualifyGoName(n *goTypeName) bool {
+	for i := len(n.ancestors) - 1; i >= 0; i-- {
+		refToken := n.ancestors[i]
+		if refToken.Name == "" || refToken.Keyword {
+			continue
+		}
+		n.ancestors = n.ancestors[:i]
+		qualifier := toGoName(refToken.Name, "")
+		if qualifier == "" || strings.HasPrefix(n.name, qualifier) {
+			continue // it would not help to disambiguate the name (e.g., a title that repeats its key)
+		}
+		n.name = toGoName(qualifier+n.name, "Schema_")
+		return true
+	}
+	n.ancestors = nil
+	for i, refToken := range n.descendants {
+		if refToken.Name == "" {
+			n.descendants = n.descendants[i+1:]
+			n.name += strconv.Itoa(refToken.Index)
+			return true
+		}
+	}
+	n.descendants = nil
+	return false
+}
+
+// goNameConflictError returns an error that reports that the schemas would all be represented by
+// Go types with the given name.
+func goNameConflictError(name string, conflicting []*goTypeName) error {
+	locations := make([]string, len(conflicting))
+	for i, n := range conflicting {
+		location := "#/" + jsonschema.EncodeReferenceTokens(n.location.rel)
+		if n.root.ID != nil {
+			location = strings.TrimSuffix(*n.root.ID, "#") + location
+		}
+		locations[i] = strconv.Quote(location)
+	}
+	sort.Strings(locations)
+	return fmt.Errorf("multiple schemas would be represented by Go types named %q (at %s); give them distinct titles or !go.typeName values", name, strings.Join(locations, ", "))
+}
+
+// schemaURI returns the location of the schema (in the root schema) as a URI, in the form used by
+// the keys of Options.TypeNames (after normalizeSchemaURI).
+func schemaURI(root *jsonschema.Schema, location schemaLocation) string {
+	doc := strings.TrimSuffix(root.BaseURI(), "#")
+	return normalizeSchemaURI(doc + "#" + jsonschema.PointerFromReferenceTokens(location.rel).URIFragment())
+}
+
+// normalizeSchemaURI returns the URI with its fragment decoded (so that it can be compared to the
+// result of schemaURI).
+func normalizeSchemaURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	fragment := u.Fragment
+	u.Fragment, u.RawFragment = "", ""
+	return u.String() + "#" + fragment
 }
 
 // toGoName converts name to a nice-looking Go exported identifier. The prefix (which must itself be
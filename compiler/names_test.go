@@ -1,7 +1,10 @@
 package compiler
 
 import (
+	"encoding/json"
 	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 func TestToGoName(t *testing.T) {
@@ -25,3 +28,22 @@ func TestToGoName(t *testing.T) {
 		}
 	}
 }
+
+func TestCompile_goNameConflicts(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(`{
+  "title": "t",
+  "type": "object",
+  "properties": {
+    "a": {"type": "object", "properties": {"x": {"type": "string"}}, "!go": {"typeName": "Same"}},
+    "b": {"type": "object", "properties": {"y": {"type": "string"}}, "!go": {"typeName": "Same"}}
+  }
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := Compile([]*jsonschema.Schema{&schema})
+	want := `multiple schemas would be represented by Go types named "Same" (at "#/properties/a", "#/properties/b"); give them distinct titles or !go.typeName values`
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
@@ -15,11 +15,20 @@ type schemaLocation struct {
 // relative location (from the root schema).
 //
 // It returns a map of each (sub)schema to its relative location.
-func parseSchema(root *jsonschema.Schema) (map[*jsonschema.Schema]schemaLocation, error) {
+func parseSchema(root *jsonschema.Schema, opts Options) (map[*jsonschema.Schema]schemaLocation, error) {
 	var err error
 	v := locationVisitor{
 		locations: map[*jsonschema.Schema]schemaLocation{},
 		err:       &err,
+		opts:      opts,
+	}
+	// The root schema's "$id" (if any) is resolved against the URI it was loaded from (if any).
+	if root.RetrievalURI != "" {
+		u, err := url.Parse(root.RetrievalURI)
+		if err != nil {
+			return nil, err
+		}
+		v.location.id = &jsonschema.ID{Base: u}
 	}
 	jsonschema.Walk(&v, root)
 	return v.locations, err
@@ -29,6 +38,7 @@ func parseSchema(root *jsonschema.Schema) (map[*jsonschema.Schema]schemaLocation
 type locationVisitor struct {
 	locations map[*jsonschema.Schema]schemaLocation
 	err       *error
+	opts      Options
 
 	location schemaLocation
 }
@@ -39,20 +49,21 @@ func (v *locationVisitor) Visit(schema *jsonschema.Schema, rel []jsonschema.Refe
 		return nil
 	}
 
-	// TODO(sqs): Don't walk if/then/else because we're not validating, and those are usually only
-	// used for validation (not for defining types).
-	if len(rel) > 0 {
+	// Don't walk if/then/else (unless the options say to) because we're not validating, and those
+	// are usually only used for validation (not for defining types).
+	if len(rel) > 0 && !v.opts.IncludeConditionalSchemas {
 		if t := rel[len(rel)-1]; t.Keyword && (t.Name == "if" || t.Name == "then" || t.Name == "else") {
 			return nil
 		}
 	}
 
-	// Skip trivial schemas.
+	// Skip trivial schemas (unless they may be represented by an existing Go type, which is looked
+	// up by location; see boundGoTypes).
 	//
 	// TODO(sqs): The ref-to-primitive test case demonstrates a downside to this simple filter: some
 	// schemas must have a description for them to be $ref'd. Make this (and/or the resolution
 	// logic) smarter.
-	if schema.IsEmpty || schema.IsNegated || (len(schema.Type) == 1 && schema.Description == nil && goBuiltinType(schema.Type[0]) != "") {
+	if schema.IsEmpty || schema.IsNegated || (len(schema.Type) == 1 && schema.Description == nil && schema.Enum == nil && goBuiltinType(schema.Type[0]) != "" && !v.mayHaveGoType(schema)) {
 		return nil
 	}
 
@@ -82,7 +93,7 @@ func (v *locationVisitor) Visit(schema *jsonschema.Schema, rel []jsonschema.Refe
 			u = v.location.id.URI().ResolveReference(u)
 		}
 		w.location.id = &jsonschema.ID{Base: u}
-	} else if v.location.id != nil {
+	} else if v.location.id != nil && len(rel) > 0 {
 		id := v.location.id.ResolveReference(rel)
 		w.location.id = &id
 	}
@@ -90,3 +101,9 @@ func (v *locationVisitor) Visit(schema *jsonschema.Schema, rel []jsonschema.Refe
 	w.locations[schema] = w.location
 	return &w
 }
+
+// mayHaveGoType reports whether schema may be bound to an existing Go type by Options.GoTypes or its
+// !go.import and !go.type extensions.
+func (v *locationVisitor) mayHaveGoType(schema *jsonschema.Schema) bool {
+	return len(v.opts.GoTypes) > 0 || (schema.Go != nil && (schema.Go.Import != "" || schema.Go.Type != ""))
+}
@@ -46,7 +46,7 @@ func TestParseSchema(t *testing.T) {
 		},
 	}
 
-	locations, err := parseSchema(schemaRoot)
+	locations, err := parseSchema(schemaRoot, Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
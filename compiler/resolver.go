@@ -8,14 +8,21 @@ import (
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
-func resolveReferences(locationsByRoot schemaLocationsByRoot) (resolutions map[*jsonschema.Schema]*jsonschema.Schema, err error) {
+// resolveReferences returns the schema that each $ref refers to.
+//
+// A $ref to a schema that is not among the root schemas (and their subschemas) but whose Go type is
+// in another package (see externalTypeForRef) is resolved to a placeholder schema, which is a key
+// in the returned externalTypes map.
+func resolveReferences(locationsByRoot schemaLocationsByRoot, opts Options) (resolutions map[*jsonschema.Schema]*jsonschema.Schema, externalTypes map[*jsonschema.Schema]externalType, err error) {
 	resolutions = map[*jsonschema.Schema]*jsonschema.Schema{}
+	externalTypes = map[*jsonschema.Schema]externalType{}
+	placeholders := map[externalType]*jsonschema.Schema{}
 	for root, locations := range locationsByRoot {
 		for schema, location := range locations {
 			if schema.Reference != nil {
 				ref, err := url.Parse(*schema.Reference)
 				if err != nil {
-					return nil, fmt.Errorf("failed to parse $ref: %w", err)
+					return nil, nil, fmt.Errorf("failed to parse $ref: %w", err)
 				}
 
 				if location.id != nil {
@@ -35,20 +42,55 @@ func resolveReferences(locationsByRoot schemaLocationsByRoot) (resolutions map[*
 				}
 
 				target := resolveReference(ref, locationsByRoot, onlyInRoot)
+				if target == nil {
+					if typ, ok := opts.externalTypeForRef(ref); ok {
+						if target = placeholders[typ]; target == nil {
+							target = &jsonschema.Schema{}
+							placeholders[typ] = target
+							externalTypes[target] = typ
+						}
+					}
+				}
 				if target != nil {
 					resolutions[schema] = target
 				} else {
-					return nil, fmt.Errorf("failed to resolve $ref: %q (dereferenced to %q)", *schema.Reference, ref)
+					return nil, nil, fmt.Errorf("failed to resolve $ref: %q (dereferenced to %q)", *schema.Reference, ref)
 				}
 			}
 		}
 	}
-	return resolutions, nil
+	return resolutions, externalTypes, nil
 }
 
 func resolveReference(ref *url.URL, locationsByRoot schemaLocationsByRoot, onlyInRoot *jsonschema.Schema) *jsonschema.Schema {
 	if isRefToMetaSchema(ref) {
-		return metaSchemaSentinel
+		return nil // always represented by jsonschema.Schema (see externalTypeForRef)
+	}
+
+	// Evaluate a JSON Pointer fragment against the schema identified by the rest of the URI (or the
+	// root schema, if the URI consists of only the fragment).
+	if strings.HasPrefix(ref.Fragment, "/") {
+		if ptr, err := jsonschema.ParsePointer(ref.Fragment); err == nil {
+			doc := *ref
+			doc.Fragment, doc.RawFragment = "", ""
+			for root, locations := range locationsByRoot {
+				if onlyInRoot != nil && root != onlyInRoot {
+					continue
+				}
+				resource := root
+				if onlyInRoot == nil {
+					resource = schemaWithID(locations, doc.String())
+				}
+				if resource == nil {
+					continue
+				}
+				if target, err := ptr.EvaluateSchema(resource); err == nil {
+					if _, ok := locations[target]; ok {
+						return target
+					}
+				}
+			}
+		}
 	}
 
 	refStr := ref.String()
@@ -66,21 +108,50 @@ func resolveReference(ref *url.URL, locationsByRoot schemaLocationsByRoot, onlyI
 					return schema
 				}
 			}
-			// TODO(sqs): Eliminate the string hackiness here.
-			if onlyInRoot != nil && "/"+jsonschema.EncodeReferenceTokens(location.rel) == ref.Fragment {
-				return schema
+			// A plain-name fragment may refer to a schema's "$anchor" (or "$dynamicAnchor") in draft
+			// 2019-09 and later.
+			for _, anchor := range []*string{schema.Anchor, schema.DynamicAnchor} {
+				if anchor == nil {
+					continue
+				}
+				if location.id != nil {
+					u := *location.id.Base
+					u.Fragment, u.RawFragment = *anchor, ""
+					if u.String() == refStr {
+						return schema
+					}
+				} else if onlyInRoot != nil && *anchor == ref.Fragment {
+					return schema
+				}
 			}
 		}
 	}
 	return nil
 }
 
-// metaSchemaSentinel is a sentinel value that refers to the JSON Schema describing JSON Schema
-// documents itself (the meta-schema). During the compiler's resolution phase, it is stored as the
-// resolution for $refs to the meta-schema. During the compiler's codegen phase, it is represented
-// by the Go type *jsonschema.Schema and an import of package jsonschema is added.
-var metaSchemaSentinel = &jsonschema.Schema{}
+// schemaWithID returns the schema whose "$id" (resolved against its ancestors' base URIs) is id,
+// or the root schema if id is the URI it was loaded from (and it has no "$id"), or nil if there is
+// none.
+func schemaWithID(locations map[*jsonschema.Schema]schemaLocation, id string) *jsonschema.Schema {
+	for schema, location := range locations {
+		// Skip schemas identified by a plain-name fragment (such as "#foo"), which do not establish
+		// a new base URI.
+		if (schema.ID == nil && len(location.rel) > 0) || location.id == nil || location.id.ReferenceTokens != nil || location.id.Base.Fragment != "" {
+			continue
+		}
+		if location.id.Base.String() == id {
+			return schema
+		}
+	}
+	return nil
+}
 
+// isRefToMetaSchema reports whether ref refers to the JSON Schema describing JSON Schema documents
+// itself (the meta-schema of one of the supported dialects).
 func isRefToMetaSchema(ref *url.URL) bool {
-	return (ref.Scheme == "http" || ref.Scheme == "https") && ref.Host == "json-schema.org" && ref.Path == "/draft-07/schema" && (ref.Fragment == "" || ref.Fragment == "/")
+	if (ref.Scheme != "http" && ref.Scheme != "https") || ref.Host != "json-schema.org" || (ref.Fragment != "" && ref.Fragment != "/") {
+		return false
+	}
+	_, ok := jsonschema.ParseDialect(ref.Host + ref.Path)
+	return ok
 }
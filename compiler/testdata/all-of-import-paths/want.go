@@ -0,0 +1,29 @@
+package p
+
+import (
+	"errors"
+	"example.com/project/audit"
+	"example.com/shared"
+)
+
+// RepoSettings description: allOf branches that refer to Go types in other packages are embedded.
+type RepoSettings struct {
+	shared.Repo
+	audit.Info
+	Private *bool `json:"private,omitempty"`
+}
+
+func (v RepoSettings) Validate() error {
+	var errs []error
+	if err := v.Repo.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+func (v *RepoSettings) ApplyDefaults() {
+	v.Repo.ApplyDefaults()
+	if v.Private == nil {
+		d1 := bool(true)
+		v.Private = &d1
+	}
+}
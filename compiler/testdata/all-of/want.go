@@ -0,0 +1,76 @@
+package p
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+type AllOf struct {
+	// Owner description: The owner is a named object with contact details.
+	Owner *Owner `json:"owner,omitempty"`
+	Pet   *Pet   `json:"pet,omitempty"`
+}
+
+func (v AllOf) Validate() error {
+	var errs []error
+	if v.Owner != nil {
+		if err := v.Owner.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("owner: %w", err))
+		}
+	}
+	if v.Pet != nil {
+		if err := v.Pet.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("pet: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type Named struct {
+	Name string `json:"name"`
+}
+
+func (v Named) Validate() error {
+	var errs []error
+	if utf8.RuneCountInString(v.Name) < 1 {
+		errs = append(errs, errors.New("name: must be at least 1 characters long"))
+	}
+	return errors.Join(errs...)
+}
+
+// Owner description: The owner is a named object with contact details.
+type Owner struct {
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name"`
+}
+
+func (v Owner) Validate() error {
+	var errs []error
+	if utf8.RuneCountInString(v.Name) < 1 {
+		errs = append(errs, errors.New("name: must be at least 1 characters long"))
+	}
+	if utf8.RuneCountInString(v.Name) > 40 {
+		errs = append(errs, errors.New("name: must be at most 40 characters long"))
+	}
+	return errors.Join(errs...)
+}
+
+type Pet struct {
+	Named
+	Age     int    `json:"age,omitempty"`
+	Species string `json:"species"`
+}
+
+func (v Pet) Validate() error {
+	var errs []error
+	if err := v.Named.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if v.Age != 0 {
+		if int64(v.Age) < 0 {
+			errs = append(errs, errors.New("age: must be greater than or equal to 0"))
+		}
+	}
+	return errors.Join(errs...)
+}
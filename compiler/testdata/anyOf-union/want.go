@@ -0,0 +1,147 @@
+package p
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type A struct {
+	String  *string
+	Boolean *bool
+}
+
+func (v A) MarshalJSON() ([]byte, error) {
+	if v.String != nil {
+		return json.Marshal(v.String)
+	}
+	if v.Boolean != nil {
+		return json.Marshal(v.Boolean)
+	}
+	return nil, errors.New("union type must have exactly 1 non-nil field value")
+}
+func (v *A) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("unexpected end of JSON input")
+	}
+	*v = A{}
+	switch data[0] {
+	case '"':
+		return json.Unmarshal(data, &v.String)
+	case 't', 'f':
+		return json.Unmarshal(data, &v.Boolean)
+	}
+	return fmt.Errorf("union type A can't hold the JSON value %s", data)
+}
+func (v A) Validate() error {
+	var errs []error
+	var n int
+	if v.String != nil {
+		n++
+	}
+	if v.Boolean != nil {
+		n++
+	}
+	if n != 1 {
+		errs = append(errs, errors.New("union type must have exactly 1 non-nil field value"))
+	}
+	return errors.Join(errs...)
+}
+
+// AnyOfUnion description: anyOf and oneOf whose branches are distinguishable by the kind of JSON value
+type AnyOfUnion struct {
+	A         *A  `json:"a,omitempty"`
+	Ambiguous any `json:"ambiguous,omitempty"`
+	// B description: A nullable union of a number, an array, and an object.
+	B *B `json:"b,omitempty"`
+}
+
+func (v AnyOfUnion) Validate() error {
+	var errs []error
+	if v.A != nil {
+		if err := v.A.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("a: %w", err))
+		}
+	}
+	if v.B != nil {
+		if err := v.B.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("b: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// B description: A nullable union of a number, an array, and an object.
+type B struct {
+	Integer *int
+	Array   []string
+	Point   *Point
+}
+
+func (v B) MarshalJSON() ([]byte, error) {
+	if v.Integer != nil {
+		return json.Marshal(v.Integer)
+	}
+	if v.Array != nil {
+		return json.Marshal(v.Array)
+	}
+	if v.Point != nil {
+		return json.Marshal(v.Point)
+	}
+	return []byte("null"), nil
+}
+func (v *B) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("unexpected end of JSON input")
+	}
+	*v = B{}
+	switch data[0] {
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return json.Unmarshal(data, &v.Integer)
+	case '[':
+		return json.Unmarshal(data, &v.Array)
+	case '{':
+		return json.Unmarshal(data, &v.Point)
+	case 'n':
+		return nil
+	}
+	return fmt.Errorf("union type B can't hold the JSON value %s", data)
+}
+func (v B) Validate() error {
+	var errs []error
+	var n int
+	if v.Integer != nil {
+		n++
+		if int64(*v.Integer) < 0 {
+			errs = append(errs, errors.New("must be greater than or equal to 0"))
+		}
+	}
+	if v.Array != nil {
+		n++
+		if len(v.Array) < 1 {
+			errs = append(errs, errors.New("must have at least 1 items"))
+		}
+	}
+	if v.Point != nil {
+		n++
+		if err := v.Point.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if n > 1 {
+		errs = append(errs, errors.New("union type must have at most 1 non-nil field value"))
+	}
+	return errors.Join(errs...)
+}
+
+type Point struct {
+	X float64 `json:"x,omitempty"`
+	Y float64 `json:"y,omitempty"`
+}
+
+func (v Point) Validate() error {
+	return nil
+}
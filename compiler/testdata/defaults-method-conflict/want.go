@@ -0,0 +1,18 @@
+package p
+
+type Job struct {
+	Retries *int  `json:"retries,omitempty"`
+	Task    *Task `json:"task,omitempty"`
+}
+
+func (v *Job) ApplyDefaults() {
+	if v.Retries == nil {
+		d1 := int(3)
+		v.Retries = &d1
+	}
+}
+
+type Task struct {
+	ApplyDefaults bool    `json:"applyDefaults,omitempty"`
+	Command       *string `json:"command,omitempty"`
+}
@@ -0,0 +1,95 @@
+package p
+
+type Client struct {
+	Retries *int `json:"retries,omitempty"`
+}
+
+func (v *Client) ApplyDefaults() {
+	if v.Retries == nil {
+		d1 := int(3)
+		v.Retries = &d1
+	}
+}
+
+type Defaults struct {
+	Backup  *Server        `json:"backup,omitempty"`
+	Client  *Client        `json:"client,omitempty"`
+	Enabled *bool          `json:"enabled,omitempty"`
+	Extra   any            `json:"extra,omitempty"`
+	Labels  map[string]int `json:"labels,omitempty"`
+	Mode    *Mode          `json:"mode,omitempty"`
+	Name    *string        `json:"name,omitempty"`
+	Port    *int           `json:"port,omitempty"`
+	Ratio   *float64       `json:"ratio,omitempty"`
+	Server  *Server        `json:"server,omitempty"`
+	Servers []*Server      `json:"servers,omitempty"`
+	Tags    []string       `json:"tags,omitempty"`
+}
+
+func (v *Defaults) ApplyDefaults() {
+	if v.Backup != nil {
+		v.Backup.ApplyDefaults()
+	}
+	if v.Client != nil {
+		v.Client.ApplyDefaults()
+	}
+	if v.Enabled == nil {
+		d1 := bool(true)
+		v.Enabled = &d1
+	}
+	if v.Extra == nil {
+		v.Extra = map[string]any{"k": []any{float64(1), "two", nil}}
+	}
+	if v.Labels == nil {
+		v.Labels = map[string]int{"x": 1}
+	}
+	if v.Mode == nil {
+		d2 := Mode("dev")
+		v.Mode = &d2
+	}
+	if v.Name == nil {
+		d3 := string("anonymous")
+		v.Name = &d3
+	}
+	if v.Port == nil {
+		d4 := int(8080)
+		v.Port = &d4
+	}
+	if v.Ratio == nil {
+		d5 := float64(0.5)
+		v.Ratio = &d5
+	}
+	if v.Server == nil {
+		v.Server = &Server{Host: "localhost"}
+	}
+	if v.Server != nil {
+		v.Server.ApplyDefaults()
+	}
+	for i6 := range v.Servers {
+		if v.Servers[i6] != nil {
+			v.Servers[i6].ApplyDefaults()
+		}
+	}
+	if v.Tags == nil {
+		v.Tags = []string{"a", "b"}
+	}
+}
+
+type Mode string
+
+const (
+	ModeDev  Mode = "dev"
+	ModeProd Mode = "prod"
+)
+
+type Server struct {
+	Host    string `json:"host"`
+	Timeout *int   `json:"timeout,omitempty"`
+}
+
+func (v *Server) ApplyDefaults() {
+	if v.Timeout == nil {
+		d1 := int(30)
+		v.Timeout = &d1
+	}
+}
@@ -0,0 +1,68 @@
+package p
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+type Coordinates struct {
+	Item0 *float64
+	Item1 *float64
+}
+
+func (v Coordinates) MarshalJSON() ([]byte, error) {
+	items := make([]any, 0, 2)
+	if v.Item0 != nil {
+		if len(items) != 0 {
+			return nil, fmt.Errorf("tuple item %d is set but item %d is not", 0, len(items))
+		}
+		items = append(items, v.Item0)
+	}
+	if v.Item1 != nil {
+		if len(items) != 1 {
+			return nil, fmt.Errorf("tuple item %d is set but item %d is not", 1, len(items))
+		}
+		items = append(items, v.Item1)
+	}
+	return json.Marshal(items)
+}
+func (v *Coordinates) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if len(items) > 2 {
+		return fmt.Errorf("Coordinates must have at most %d items, got %d", 2, len(items))
+	}
+	*v = Coordinates{}
+	if len(items) > 0 {
+		if err := json.Unmarshal(items[0], &v.Item0); err != nil {
+			return fmt.Errorf("tuple item %d: %w", 0, err)
+		}
+	}
+	if len(items) > 1 {
+		if err := json.Unmarshal(items[1], &v.Item1); err != nil {
+			return fmt.Errorf("tuple item %d: %w", 1, err)
+		}
+	}
+	return nil
+}
+
+type Customer struct {
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+type LineItem struct {
+	Quantity int    `json:"quantity,omitempty"`
+	Sku      string `json:"sku"`
+}
+type Order struct {
+	Coordinates *Coordinates       `json:"coordinates,omitempty"`
+	Customer    Customer           `json:"customer"`
+	Items       []*LineItem        `json:"items,omitempty"`
+	Meta        *jsonschema.Schema `json:"meta,omitempty"`
+}
@@ -0,0 +1,41 @@
+package p
+
+import (
+	"encoding/json"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+	"sync"
+)
+
+// EmbedSchema description: A schema that is embedded in the generated code.
+type EmbedSchema struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (v EmbedSchema) Schema() *jsonschema.Schema {
+	return parsedEmbedSchemaSchema()
+}
+
+// EmbedSchemaSchemaJSON is the JSON Schema that EmbedSchema was generated from.
+const EmbedSchemaSchemaJSON = `{
+  "$id": "https://example.com/embed-schema",
+  "description": "A schema that is embedded in the generated code.",
+  "properties": {
+    "name": {
+      "type": "string",
+      "x-editor": {
+        "autocomplete": true,
+        "rank": 9007199254740993
+      }
+    }
+  },
+  "title": "embed-schema",
+  "type": "object"
+}`
+
+var parsedEmbedSchemaSchema = sync.OnceValue(func() *jsonschema.Schema {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(EmbedSchemaSchemaJSON), &schema); err != nil {
+		panic("invalid embedded JSON Schema: " + err.Error())
+	}
+	return &schema
+})
@@ -0,0 +1,134 @@
+package p
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Color description: A color.
+type Color string
+
+const (
+	ColorRed   Color = "red"
+	ColorGreen Color = "green"
+)
+
+func (v *Color) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var x string
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	switch Color(x) {
+	case ColorRed, ColorGreen:
+		*v = Color(x)
+		return nil
+	}
+	return fmt.Errorf("invalid Color value %q (must be one of %s)", x, "\"red\", \"green\"")
+}
+
+type Enum struct {
+	Color    Color    `json:"color,omitempty"`
+	Colors   []Color  `json:"colors,omitempty"`
+	Level    Level    `json:"level"`
+	Mixed    any      `json:"mixed,omitempty"`
+	Op       Op       `json:"op,omitempty"`
+	Protocol Protocol `json:"protocol,omitempty"`
+	Untyped  Untyped  `json:"untyped,omitempty"`
+}
+type Level int
+
+const (
+	LevelMinus1 Level = -1
+	Level0      Level = 0
+	Level1      Level = 1
+	Level2      Level = 2
+)
+
+func (v *Level) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var x int
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	switch Level(x) {
+	case LevelMinus1, Level0, Level1, Level2:
+		*v = Level(x)
+		return nil
+	}
+	return fmt.Errorf("invalid Level value %d (must be one of %s)", x, "-1, 0, 1, 2")
+}
+
+type Op string
+
+const (
+	Op_0 Op = "+"
+	Op_1 Op = "-"
+)
+
+func (v *Op) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var x string
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	switch Op(x) {
+	case Op_0, Op_1:
+		*v = Op(x)
+		return nil
+	}
+	return fmt.Errorf("invalid Op value %q (must be one of %s)", x, "\"+\", \"-\"")
+}
+
+type Protocol string
+
+const (
+	ProtocolHttp  Protocol = "http"
+	ProtocolHttps Protocol = "https"
+)
+
+func (v *Protocol) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var x string
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	switch Protocol(x) {
+	case ProtocolHttp, ProtocolHttps:
+		*v = Protocol(x)
+		return nil
+	}
+	return fmt.Errorf("invalid Protocol value %q (must be one of %s)", x, "\"http\", \"https\"")
+}
+
+type Untyped string
+
+const (
+	UntypedA     Untyped = "a"
+	UntypedBC    Untyped = "b-c"
+	UntypedEmpty Untyped = ""
+)
+
+func (v *Untyped) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var x string
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	switch Untyped(x) {
+	case UntypedA, UntypedBC, UntypedEmpty:
+		*v = Untyped(x)
+		return nil
+	}
+	return fmt.Errorf("invalid Untyped value %q (must be one of %s)", x, "\"a\", \"b-c\", \"\"")
+}
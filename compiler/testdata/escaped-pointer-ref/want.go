@@ -0,0 +1,20 @@
+package p
+
+type Paths struct {
+	Other   *SpaceField   `json:"other,omitempty"`
+	Percent *PercentField `json:"percent,omitempty"`
+	Slash   *SlashField   `json:"slash,omitempty"`
+	Tilde   *TildeField   `json:"tilde,omitempty"`
+}
+type PercentField struct {
+	C string `json:"c,omitempty"`
+}
+type SlashField struct {
+	A string `json:"a,omitempty"`
+}
+type SpaceField struct {
+	D string `json:"d,omitempty"`
+}
+type TildeField struct {
+	B string `json:"b,omitempty"`
+}
@@ -0,0 +1,15 @@
+package p
+
+type Address struct {
+	City   string `json:"city,omitempty"`
+	Street string `json:"street,omitempty"`
+}
+type Customer struct {
+	BillingAddress *Address `json:"billingAddress,omitempty"`
+	LastOrder      *Order   `json:"lastOrder,omitempty"`
+	Name           string   `json:"name,omitempty"`
+}
+type Order struct {
+	Customer        *Customer `json:"customer,omitempty"`
+	ShippingAddress *Address  `json:"shippingAddress,omitempty"`
+}
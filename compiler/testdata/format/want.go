@@ -0,0 +1,37 @@
+package p
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sourcegraph/go-jsonschema/format"
+	"net/netip"
+	"time"
+)
+
+type Event struct {
+	Addresses   []netip.Addr         `json:"addresses,omitempty"`
+	Date        string               `json:"date,omitempty"`
+	Email       string               `json:"email,omitempty"`
+	Homepage    *format.URI          `json:"homepage,omitempty"`
+	Id          format.UUID          `json:"id"`
+	Ipv6Address *netip.Addr          `json:"ipv6Address,omitempty"`
+	Link        *format.URIReference `json:"link,omitempty"`
+	Pattern     *format.Regexp       `json:"pattern,omitempty"`
+	Time        time.Time            `json:"time"`
+	Timeout     *format.Duration     `json:"timeout,omitempty"`
+}
+
+func (v Event) Validate() error {
+	var errs []error
+	for i1, e2 := range v.Addresses {
+		if !e2.Is4() {
+			errs = append(errs, fmt.Errorf("addresses/%d: must be an IPv4 address", i1))
+		}
+	}
+	if v.Ipv6Address != nil {
+		if !(*v.Ipv6Address).Is6() || (*v.Ipv6Address).Zone() != "" {
+			errs = append(errs, errors.New("ipv6Address: must be an IPv6 address"))
+		}
+	}
+	return errors.Join(errs...)
+}
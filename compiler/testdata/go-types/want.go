@@ -0,0 +1,40 @@
+package p
+
+import (
+	"errors"
+	"example.com/project/repo"
+	users "example.com/project/users/v2"
+	"time"
+	"unicode/utf8"
+)
+
+type Branch struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (v Branch) Validate() error {
+	return nil
+}
+func (v *Branch) ApplyDefaults() {
+}
+
+type Config struct {
+	Mirrors []*repo.Repo  `json:"mirrors,omitempty"`
+	Mode    *Mode         `json:"mode,omitempty"`
+	Name    *string       `json:"name,omitempty"`
+	Owner   *users.User   `json:"owner,omitempty"`
+	Repo    *repo.Repo    `json:"repo,omitempty"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+func (v Config) Validate() error {
+	var errs []error
+	if v.Name != nil {
+		if utf8.RuneCountInString(*v.Name) < 1 {
+			errs = append(errs, errors.New("name: must be at least 1 characters long"))
+		}
+	}
+	return errors.Join(errs...)
+}
+func (v *Config) ApplyDefaults() {
+}
@@ -0,0 +1,18 @@
+package p
+
+import (
+	legacy "example.com/legacy/v2"
+	"example.com/shared"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+type Config struct {
+	Anchored   *shared.PrimaryColor `json:"anchored,omitempty"`
+	Labels     *shared.Labels       `json:"labels,omitempty"`
+	LegacyItem *legacy.Item         `json:"legacyItem,omitempty"`
+	Owner      *shared.Owner        `json:"owner,omitempty"`
+	Repo       *shared.Repo         `json:"repo,omitempty"`
+	Schema     *jsonschema.Schema   `json:"schema,omitempty"`
+	Settings   *shared.Prefs        `json:"settings,omitempty"`
+	Tags       []*shared.Tag        `json:"tags,omitempty"`
+}
@@ -0,0 +1,21 @@
+package p
+
+import (
+	legacy "example.com/legacy/v2"
+	"example.com/shared"
+)
+
+type Config struct {
+	Item       *Item              `json:"item,omitempty"`
+	LegacyItem *legacy.Item       `json:"legacyItem,omitempty"`
+	Repo       *shared.Repository `json:"repo,omitempty"`
+	Settings   *Prefs             `json:"settings,omitempty"`
+	Tags       []*shared.Tag      `json:"tags,omitempty"`
+	Visibility shared.Visibility  `json:"visibility,omitempty"`
+}
+type Item struct {
+	Name string `json:"name,omitempty"`
+}
+type Prefs struct {
+	Verbose bool `json:"verbose,omitempty"`
+}
@@ -0,0 +1,69 @@
+package p
+
+import "errors"
+
+type Packet struct {
+	Checksum  int     `json:"checksum,omitempty"`
+	Count     int     `json:"count,omitempty"`
+	Delta     int     `json:"delta,omitempty"`
+	Length    uint64  `json:"length,omitempty"`
+	Level     *uint8  `json:"level,omitempty"`
+	Nonce     uint64  `json:"nonce,omitempty"`
+	Offset    int8    `json:"offset,omitempty"`
+	Port      *uint16 `json:"port,omitempty"`
+	Sequence  uint64  `json:"sequence,omitempty"`
+	Timestamp int64   `json:"timestamp,omitempty"`
+	Ttl       uint8   `json:"ttl"`
+}
+
+func (v Packet) Validate() error {
+	var errs []error
+	if v.Checksum != 0 {
+		if int64(v.Checksum) < 0 {
+			errs = append(errs, errors.New("checksum: must be greater than or equal to 0"))
+		}
+		if int64(v.Checksum) > 255 {
+			errs = append(errs, errors.New("checksum: must be less than or equal to 255"))
+		}
+	}
+	if v.Delta != 0 {
+		if int64(v.Delta) > 1000 {
+			errs = append(errs, errors.New("delta: must be less than or equal to 1000"))
+		}
+	}
+	if v.Level != nil {
+		if *v.Level <= 0 {
+			errs = append(errs, errors.New("level: must be greater than 0.5"))
+		}
+		if *v.Level > 10 {
+			errs = append(errs, errors.New("level: must be less than or equal to 10"))
+		}
+	}
+	if v.Nonce != 0 {
+		if v.Nonce > 9007199254740992 {
+			errs = append(errs, errors.New("nonce: must be less than or equal to 9007199254740992"))
+		}
+		if v.Nonce%2 != 0 {
+			errs = append(errs, errors.New("nonce: must be a multiple of 2"))
+		}
+	}
+	if v.Port != nil {
+		if *v.Port < 1 {
+			errs = append(errs, errors.New("port: must be greater than or equal to 1"))
+		}
+	}
+	if v.Sequence != 0 {
+		if v.Sequence > 4294967296 {
+			errs = append(errs, errors.New("sequence: must be less than or equal to 4294967296"))
+		}
+	}
+	if v.Timestamp != 0 {
+		if v.Timestamp < -9007199254740991 {
+			errs = append(errs, errors.New("timestamp: must be greater than or equal to -9007199254740991"))
+		}
+		if v.Timestamp > 9007199254740991 {
+			errs = append(errs, errors.New("timestamp: must be less than or equal to 9007199254740991"))
+		}
+	}
+	return errors.Join(errs...)
+}
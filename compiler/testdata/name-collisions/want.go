@@ -0,0 +1,25 @@
+package p
+
+type Extensions struct {
+	Items *ExtensionsItems `json:"items,omitempty"`
+}
+type ExtensionsItems struct {
+	B string `json:"b,omitempty"`
+}
+type Items struct {
+	D string `json:"d,omitempty"`
+}
+type NameCollisions struct {
+	Extensions *Extensions   `json:"extensions,omitempty"`
+	Renamed    *RenamedItems `json:"renamed,omitempty"`
+	Settings   *Settings     `json:"settings,omitempty"`
+}
+type RenamedItems struct {
+	C string `json:"c,omitempty"`
+}
+type Settings struct {
+	Items *SettingsItems `json:"items,omitempty"`
+}
+type SettingsItems struct {
+	A string `json:"a,omitempty"`
+}
@@ -0,0 +1,77 @@
+package p
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type Circle struct {
+	Kind   string  `json:"kind"`
+	Name   string  `json:"name"`
+	Radius float64 `json:"radius,omitempty"`
+}
+type Hexagon struct {
+	Kind any     `json:"kind"`
+	Name string  `json:"name"`
+	Side float64 `json:"side,omitempty"`
+}
+
+// OneOfInferred description: oneOf whose branches are discriminated by a common property (without the !go extension)
+type OneOfInferred struct {
+	NotAUnion any    `json:"notAUnion,omitempty"`
+	Shape     *Shape `json:"shape,omitempty"`
+}
+type Shape struct {
+	Circle   *Circle
+	Square   *Square
+	Hexagon  *Hexagon
+	Triangle *Triangle
+}
+
+func (v Shape) MarshalJSON() ([]byte, error) {
+	if v.Circle != nil {
+		return json.Marshal(v.Circle)
+	}
+	if v.Square != nil {
+		return json.Marshal(v.Square)
+	}
+	if v.Hexagon != nil {
+		return json.Marshal(v.Hexagon)
+	}
+	if v.Triangle != nil {
+		return json.Marshal(v.Triangle)
+	}
+	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
+}
+func (v *Shape) UnmarshalJSON(data []byte) error {
+	var d struct {
+		DiscriminantProperty string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	switch d.DiscriminantProperty {
+	case "circle":
+		return json.Unmarshal(data, &v.Circle)
+	case "hexagon":
+		return json.Unmarshal(data, &v.Hexagon)
+	case "square":
+		return json.Unmarshal(data, &v.Square)
+	case "triangle":
+		return json.Unmarshal(data, &v.Triangle)
+	}
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "kind", []string{"circle", "square", "hexagon", "triangle"})
+}
+
+type Square struct {
+	Kind any     `json:"kind"`
+	Name string  `json:"name"`
+	Side float64 `json:"side,omitempty"`
+}
+type Triangle struct {
+	Base   float64 `json:"base,omitempty"`
+	Height float64 `json:"height,omitempty"`
+	Kind   string  `json:"kind"`
+	Name   string  `json:"name"`
+}
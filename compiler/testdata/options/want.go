@@ -0,0 +1,52 @@
+package p
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+type APICustomer struct {
+	Name *string `json:"name,omitempty"`
+}
+
+func (v APICustomer) Validate() error {
+	return nil
+}
+
+type APIGiftOrder struct {
+	Recipient *string `json:"recipient,omitempty"`
+}
+
+func (v APIGiftOrder) Validate() error {
+	return nil
+}
+
+type APIOrder struct {
+	Customer *APICustomer `json:"customer,omitempty"`
+	Gift     *bool        `json:"gift,omitempty"`
+	Id       int64        `json:"id"`
+	Note     *string      `json:"note,omitempty"`
+	Price    *float32     `json:"price,omitempty"`
+	Tags     []string     `json:"tags,omitempty"`
+}
+
+func (v APIOrder) Validate() error {
+	var errs []error
+	if v.Customer != nil {
+		if err := v.Customer.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("customer: %w", err))
+		}
+	}
+	if v.Note != nil {
+		if utf8.RuneCountInString(*v.Note) > 100 {
+			errs = append(errs, errors.New("note: must be at most 100 characters long"))
+		}
+	}
+	if v.Price != nil {
+		if float64(*v.Price) < 0 {
+			errs = append(errs, errors.New("price: must be greater than or equal to 0"))
+		}
+	}
+	return errors.Join(errs...)
+}
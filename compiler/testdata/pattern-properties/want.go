@@ -0,0 +1,146 @@
+package p
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+type Labels struct {
+	Default           string            `json:"default,omitempty"`
+	PatternProperties map[string]string `json:"-"` // properties matching "^[A-Z]+$", "^[a-z]+$"
+}
+
+func (v Labels) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any)
+	for k, v := range v.PatternProperties {
+		m[k] = v
+	}
+	type wrapper Labels
+	b, err := json.Marshal(wrapper(v))
+	if err != nil {
+		return nil, err
+	}
+	var m2 map[string]any
+	if err := json.Unmarshal(b, &m2); err != nil {
+		return nil, err
+	}
+	for k, v := range m2 {
+		m[k] = v
+	}
+	return json.Marshal(m)
+}
+func (v *Labels) UnmarshalJSON(data []byte) error {
+	type wrapper Labels
+	var s wrapper
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*v = Labels(s)
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	delete(m, "default")
+	for k, raw := range m {
+		if patternLabels0.MatchString(k) {
+			var vv string
+			if err := json.Unmarshal(raw, &vv); err != nil {
+				return fmt.Errorf("property %q: %w", k, err)
+			}
+			if v.PatternProperties == nil {
+				v.PatternProperties = make(map[string]string)
+			}
+			v.PatternProperties[k] = vv
+			continue
+		}
+	}
+	return nil
+}
+
+type PatternProperties struct {
+	Counts             map[string]int     `json:"counts,omitempty"`
+	Labels             *Labels            `json:"labels,omitempty"`
+	Name               string             `json:"name,omitempty"`
+	PatternProperties0 map[string]float64 `json:"-"` // properties matching "^n-"
+	PatternProperties1 map[string]string  `json:"-"` // properties matching "^x-"
+	Additional         map[string]bool    `json:"-"` // additionalProperties not explicitly defined in the schema
+}
+
+func (v PatternProperties) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any)
+	for k, v := range v.PatternProperties0 {
+		m[k] = v
+	}
+	for k, v := range v.PatternProperties1 {
+		m[k] = v
+	}
+	for k, v := range v.Additional {
+		m[k] = v
+	}
+	type wrapper PatternProperties
+	b, err := json.Marshal(wrapper(v))
+	if err != nil {
+		return nil, err
+	}
+	var m2 map[string]any
+	if err := json.Unmarshal(b, &m2); err != nil {
+		return nil, err
+	}
+	for k, v := range m2 {
+		m[k] = v
+	}
+	return json.Marshal(m)
+}
+func (v *PatternProperties) UnmarshalJSON(data []byte) error {
+	type wrapper PatternProperties
+	var s wrapper
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*v = PatternProperties(s)
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	delete(m, "counts")
+	delete(m, "labels")
+	delete(m, "name")
+	for k, raw := range m {
+		if patternPatternProperties0.MatchString(k) {
+			var vv float64
+			if err := json.Unmarshal(raw, &vv); err != nil {
+				return fmt.Errorf("property %q: %w", k, err)
+			}
+			if v.PatternProperties0 == nil {
+				v.PatternProperties0 = make(map[string]float64)
+			}
+			v.PatternProperties0[k] = vv
+			continue
+		}
+		if patternPatternProperties1.MatchString(k) {
+			var vv string
+			if err := json.Unmarshal(raw, &vv); err != nil {
+				return fmt.Errorf("property %q: %w", k, err)
+			}
+			if v.PatternProperties1 == nil {
+				v.PatternProperties1 = make(map[string]string)
+			}
+			v.PatternProperties1[k] = vv
+			continue
+		}
+		var vv bool
+		if err := json.Unmarshal(raw, &vv); err != nil {
+			return fmt.Errorf("property %q: %w", k, err)
+		}
+		if v.Additional == nil {
+			v.Additional = make(map[string]bool)
+		}
+		v.Additional[k] = vv
+	}
+	return nil
+}
+
+var patternLabels0 = regexp.MustCompile("(?:^[A-Z]+$)|(?:^[a-z]+$)")
+var patternPatternProperties0 = regexp.MustCompile("^n-")
+var patternPatternProperties1 = regexp.MustCompile("^x-")
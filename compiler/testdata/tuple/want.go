@@ -0,0 +1,219 @@
+package p
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+type Cell struct {
+	Value string `json:"value,omitempty"`
+}
+
+func (v Cell) Validate() error {
+	return nil
+}
+
+type Open struct {
+	Item0      *bool
+	Additional []any // items after the positional items
+}
+
+func (v Open) MarshalJSON() ([]byte, error) {
+	items := make([]any, 0, 1)
+	if v.Item0 != nil {
+		if len(items) != 0 {
+			return nil, fmt.Errorf("tuple item %d is set but item %d is not", 0, len(items))
+		}
+		items = append(items, v.Item0)
+	}
+	if len(v.Additional) > 0 && len(items) != 1 {
+		return nil, fmt.Errorf("tuple additional items are set but item %d is not", len(items))
+	}
+	for _, item := range v.Additional {
+		items = append(items, item)
+	}
+	return json.Marshal(items)
+}
+func (v *Open) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	*v = Open{}
+	if len(items) > 0 {
+		if err := json.Unmarshal(items[0], &v.Item0); err != nil {
+			return fmt.Errorf("tuple item %d: %w", 0, err)
+		}
+	}
+	if len(items) > 1 {
+		additional, err := json.Marshal(items[1:])
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(additional, &v.Additional); err != nil {
+			return fmt.Errorf("tuple additional items: %w", err)
+		}
+	}
+	return nil
+}
+func (v Open) Validate() error {
+	return nil
+}
+
+// Point description: A point with an optional label.
+type Point struct {
+	X     float64
+	Y     float64
+	Item2 *string
+}
+
+func (v Point) MarshalJSON() ([]byte, error) {
+	items := make([]any, 0, 3)
+	items = append(items, v.X)
+	items = append(items, v.Y)
+	if v.Item2 != nil {
+		if len(items) != 2 {
+			return nil, fmt.Errorf("tuple item %d is set but item %d is not", 2, len(items))
+		}
+		items = append(items, v.Item2)
+	}
+	return json.Marshal(items)
+}
+func (v *Point) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if len(items) < 2 {
+		return fmt.Errorf("Point must have at least %d items, got %d", 2, len(items))
+	}
+	if len(items) > 3 {
+		return fmt.Errorf("Point must have at most %d items, got %d", 3, len(items))
+	}
+	*v = Point{}
+	if err := json.Unmarshal(items[0], &v.X); err != nil {
+		return fmt.Errorf("tuple item %d: %w", 0, err)
+	}
+	if err := json.Unmarshal(items[1], &v.Y); err != nil {
+		return fmt.Errorf("tuple item %d: %w", 1, err)
+	}
+	if len(items) > 2 {
+		if err := json.Unmarshal(items[2], &v.Item2); err != nil {
+			return fmt.Errorf("tuple item %d: %w", 2, err)
+		}
+	}
+	return nil
+}
+func (v Point) Validate() error {
+	var errs []error
+	if v.Item2 != nil {
+		if utf8.RuneCountInString(*v.Item2) < 1 {
+			errs = append(errs, errors.New("2: must be at least 1 characters long"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type Row struct {
+	Item0      string
+	Item1      *Cell
+	Additional []int // items after the positional items
+}
+
+func (v Row) MarshalJSON() ([]byte, error) {
+	items := make([]any, 0, 2)
+	items = append(items, v.Item0)
+	if v.Item1 != nil {
+		if len(items) != 1 {
+			return nil, fmt.Errorf("tuple item %d is set but item %d is not", 1, len(items))
+		}
+		items = append(items, v.Item1)
+	}
+	if len(v.Additional) > 0 && len(items) != 2 {
+		return nil, fmt.Errorf("tuple additional items are set but item %d is not", len(items))
+	}
+	for _, item := range v.Additional {
+		items = append(items, item)
+	}
+	return json.Marshal(items)
+}
+func (v *Row) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if len(items) < 1 {
+		return fmt.Errorf("Row must have at least %d items, got %d", 1, len(items))
+	}
+	*v = Row{}
+	if err := json.Unmarshal(items[0], &v.Item0); err != nil {
+		return fmt.Errorf("tuple item %d: %w", 0, err)
+	}
+	if len(items) > 1 {
+		if err := json.Unmarshal(items[1], &v.Item1); err != nil {
+			return fmt.Errorf("tuple item %d: %w", 1, err)
+		}
+	}
+	if len(items) > 2 {
+		additional, err := json.Marshal(items[2:])
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(additional, &v.Additional); err != nil {
+			return fmt.Errorf("tuple additional items: %w", err)
+		}
+	}
+	return nil
+}
+func (v Row) Validate() error {
+	var errs []error
+	if v.Item1 != nil {
+		if err := v.Item1.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("1: %w", err))
+		}
+	}
+	for i1, e2 := range v.Additional {
+		if int64(e2) < 0 {
+			errs = append(errs, fmt.Errorf("%d: must be greater than or equal to 0", 2+i1))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type Tuple struct {
+	Open *Open `json:"open,omitempty"`
+	// Point description: A point with an optional label.
+	Point *Point `json:"point,omitempty"`
+	Row   *Row   `json:"row,omitempty"`
+}
+
+func (v Tuple) Validate() error {
+	var errs []error
+	if v.Open != nil {
+		if err := v.Open.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("open: %w", err))
+		}
+	}
+	if v.Point != nil {
+		if err := v.Point.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("point: %w", err))
+		}
+	}
+	if v.Row != nil {
+		if err := v.Row.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("row: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
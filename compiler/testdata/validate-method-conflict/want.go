@@ -0,0 +1,27 @@
+package p
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+type Pipeline struct {
+	Name  *string `json:"name,omitempty"`
+	Tasks []*Task `json:"tasks,omitempty"`
+}
+
+func (v Pipeline) Validate() error {
+	var errs []error
+	if v.Name != nil {
+		if utf8.RuneCountInString(*v.Name) < 1 {
+			errs = append(errs, errors.New("name: must be at least 1 characters long"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type Task struct {
+	Command *string `json:"command,omitempty"`
+	// Validate description: Whether to validate the task's output.
+	Validate bool `json:"validate,omitempty"`
+}
@@ -0,0 +1,278 @@
+package p
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+)
+
+type Child struct {
+	Id                string            `json:"id"`
+	PatternProperties map[string]string `json:"-"` // properties matching "^x-"
+	Additional        map[string]int    `json:"-"` // additionalProperties not explicitly defined in the schema
+}
+
+func (v Child) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any)
+	for k, v := range v.PatternProperties {
+		m[k] = v
+	}
+	for k, v := range v.Additional {
+		m[k] = v
+	}
+	type wrapper Child
+	b, err := json.Marshal(wrapper(v))
+	if err != nil {
+		return nil, err
+	}
+	var m2 map[string]any
+	if err := json.Unmarshal(b, &m2); err != nil {
+		return nil, err
+	}
+	for k, v := range m2 {
+		m[k] = v
+	}
+	return json.Marshal(m)
+}
+func (v *Child) UnmarshalJSON(data []byte) error {
+	type wrapper Child
+	var s wrapper
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*v = Child(s)
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	delete(m, "id")
+	for k, raw := range m {
+		if patternChild0.MatchString(k) {
+			var vv string
+			if err := json.Unmarshal(raw, &vv); err != nil {
+				return fmt.Errorf("property %q: %w", k, err)
+			}
+			if v.PatternProperties == nil {
+				v.PatternProperties = make(map[string]string)
+			}
+			v.PatternProperties[k] = vv
+			continue
+		}
+		var vv int
+		if err := json.Unmarshal(raw, &vv); err != nil {
+			return fmt.Errorf("property %q: %w", k, err)
+		}
+		if v.Additional == nil {
+			v.Additional = make(map[string]int)
+		}
+		v.Additional[k] = vv
+	}
+	return nil
+}
+func (v Child) Validate() error {
+	var errs []error
+	if utf8.RuneCountInString(v.Id) < 3 {
+		errs = append(errs, errors.New("id: must be at least 3 characters long"))
+	}
+	for k1, e2 := range v.PatternProperties {
+		if patternChild0.MatchString(k1) {
+			if utf8.RuneCountInString(e2) < 1 {
+				errs = append(errs, fmt.Errorf("%s: must be at least 1 characters long", k1))
+			}
+		}
+	}
+	for k3, e4 := range v.Additional {
+		if int64(e4) > 5 {
+			errs = append(errs, fmt.Errorf("%s: must be less than or equal to 5", k3))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type Circle struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius,omitempty"`
+}
+
+func (v Circle) Validate() error {
+	var errs []error
+	if v.Kind != "circle" {
+		errs = append(errs, errors.New("kind: must be equal to \"circle\""))
+	}
+	if v.Radius != 0 {
+		if v.Radius < 0 {
+			errs = append(errs, errors.New("radius: must be greater than or equal to 0"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type Shapes struct {
+	Circle *Circle
+	Square *Square
+}
+
+func (v Shapes) MarshalJSON() ([]byte, error) {
+	if v.Circle != nil {
+		return json.Marshal(v.Circle)
+	}
+	if v.Square != nil {
+		return json.Marshal(v.Square)
+	}
+	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
+}
+func (v *Shapes) UnmarshalJSON(data []byte) error {
+	var d struct {
+		DiscriminantProperty string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	switch d.DiscriminantProperty {
+	case "circle":
+		return json.Unmarshal(data, &v.Circle)
+	case "square":
+		return json.Unmarshal(data, &v.Square)
+	}
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "kind", []string{"circle", "square"})
+}
+func (v Shapes) Validate() error {
+	var errs []error
+	var n int
+	if v.Circle != nil {
+		n++
+		if err := v.Circle.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if v.Square != nil {
+		n++
+		if err := v.Square.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if n != 1 {
+		errs = append(errs, errors.New("tagged union type must have exactly 1 non-nil field value"))
+	}
+	return errors.Join(errs...)
+}
+
+type Square struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side,omitempty"`
+}
+
+func (v Square) Validate() error {
+	var errs []error
+	if v.Kind != "square" {
+		errs = append(errs, errors.New("kind: must be equal to \"square\""))
+	}
+	if v.Side != 0 {
+		if v.Side < 0 {
+			errs = append(errs, errors.New("side: must be greater than or equal to 0"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type Validate struct {
+	Child    *Child         `json:"child,omitempty"`
+	Children []*Child       `json:"children,omitempty"`
+	Limit    *int           `json:"limit,omitempty"`
+	Name     string         `json:"name"`
+	Port     *int           `json:"port,omitempty"`
+	Protocol *string        `json:"protocol,omitempty"`
+	Quotas   map[string]int `json:"quotas,omitempty"`
+	Ratio    *float64       `json:"ratio,omitempty"`
+	Shapes   []Shapes       `json:"shapes,omitempty"`
+	Tags     []string       `json:"tags"`
+}
+
+func (v Validate) Validate() error {
+	var errs []error
+	if v.Child != nil {
+		if err := v.Child.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("child: %w", err))
+		}
+	}
+	for i1, e2 := range v.Children {
+		if e2 != nil {
+			if err := e2.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("children/%d: %w", i1, err))
+			}
+		}
+	}
+	if v.Limit != nil {
+		if int64(*v.Limit) > 10 {
+			errs = append(errs, errors.New("limit: must be less than or equal to 10"))
+		}
+	}
+	if utf8.RuneCountInString(v.Name) < 1 {
+		errs = append(errs, errors.New("name: must be at least 1 characters long"))
+	}
+	if utf8.RuneCountInString(v.Name) > 10 {
+		errs = append(errs, errors.New("name: must be at most 10 characters long"))
+	}
+	if !patternValidate0.MatchString(v.Name) {
+		errs = append(errs, errors.New("name: must match the pattern \"^[a-z]+$\""))
+	}
+	if v.Port != nil {
+		if int64(*v.Port) < 1 {
+			errs = append(errs, errors.New("port: must be greater than or equal to 1"))
+		}
+		if int64(*v.Port) > 65535 {
+			errs = append(errs, errors.New("port: must be less than or equal to 65535"))
+		}
+	}
+	if v.Protocol != nil {
+		if *v.Protocol != "http" && *v.Protocol != "https" {
+			errs = append(errs, errors.New("protocol: must be one of \"http\", \"https\""))
+		}
+	}
+	for k3, e4 := range v.Quotas {
+		if int64(e4) < 0 {
+			errs = append(errs, fmt.Errorf("quotas/%s: must be greater than or equal to 0", k3))
+		}
+	}
+	if v.Ratio != nil {
+		if *v.Ratio <= 0 {
+			errs = append(errs, errors.New("ratio: must be greater than 0"))
+		}
+		if q5 := *v.Ratio / 0.25; math.Abs(q5-math.Round(q5)) > 1e-9 {
+			errs = append(errs, errors.New("ratio: must be a multiple of 0.25"))
+		}
+	}
+	for i6, e7 := range v.Shapes {
+		if err := e7.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("shapes/%d: %w", i6, err))
+		}
+	}
+	if v.Tags == nil {
+		errs = append(errs, errors.New("missing required property \"tags\""))
+	}
+	if len(v.Tags) < 1 {
+		errs = append(errs, errors.New("tags: must have at least 1 items"))
+	}
+unique10:
+	for i8 := range v.Tags {
+		for j9 := i8 + 1; j9 < len(v.Tags); j9++ {
+			if reflect.DeepEqual(v.Tags[i8], v.Tags[j9]) {
+				errs = append(errs, errors.New("tags: must not contain duplicate items"))
+				break unique10
+			}
+		}
+	}
+	for i11, e12 := range v.Tags {
+		if utf8.RuneCountInString(e12) < 2 {
+			errs = append(errs, fmt.Errorf("tags/%d: must be at least 2 characters long", i11))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var patternChild0 = regexp.MustCompile("^x-")
+var patternValidate0 = regexp.MustCompile("^[a-z]+$")
@@ -42,6 +42,35 @@ func goBuiltinType(typ jsonschema.PrimitiveType) string {
 	}
 }
 
+// goBuiltinType returns the Go builtin type that represents values of the JSON Schema primitive
+// type, taking Options.BuiltinTypes into account.
+func (g *generator) goBuiltinType(typ jsonschema.PrimitiveType) string {
+	if goType, ok := g.opts.BuiltinTypes[typ]; ok {
+		return goType
+	}
+	return goBuiltinType(typ)
+}
+
+// isAllowedBuiltinType reports whether the Go builtin type may represent values of the JSON Schema
+// primitive type (see Options.BuiltinTypes).
+func isAllowedBuiltinType(typ jsonschema.PrimitiveType, goType string) bool {
+	switch typ {
+	case jsonschema.BooleanType:
+		return goType == "bool"
+	case jsonschema.NumberType:
+		return goType == "float32" || goType == "float64"
+	case jsonschema.IntegerType:
+		for _, t := range goIntegerTypes {
+			if t.name == goType {
+				return true
+			}
+		}
+	case jsonschema.StringType:
+		return goType == "string"
+	}
+	return false
+}
+
 func isEmittedAsGoNamedType(schema *jsonschema.Schema) bool {
 	return isTypeOrNull(schema, jsonschema.ObjectType)
 }
@@ -59,7 +88,15 @@ func isBasicType(x ast.Expr) bool {
 	if !ok {
 		return false
 	}
-	return t.Name == "string" || t.Name == "bool" || t.Name == "int" || t.Name == "float64"
+	if t.Name == "string" || t.Name == "bool" || t.Name == "float32" || t.Name == "float64" {
+		return true
+	}
+	for _, it := range goIntegerTypes {
+		if t.Name == it.name {
+			return true
+		}
+	}
+	return false
 }
 
 func forceGoPointer(schema *jsonschema.Schema) bool { return schema.Go != nil && schema.Go.Pointer }
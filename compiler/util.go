@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"sort"
 	"text/template"
 )
 
@@ -40,3 +41,12 @@ func parseFuncLitToFuncDecl(funcLitExpr string) (*ast.FuncDecl, error) {
 		Body: funcLit.Body,
 	}, nil
 }
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
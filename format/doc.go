@@ -0,0 +1,6 @@
+// Package format provides Go types that hold values of JSON Schema string formats (such as "uri",
+// "duration", and "uuid") and marshal/unmarshal to/from JSON strings using encoding/json.
+//
+// Code generated by the compiler refers to these types when it is configured to represent string
+// formats by Go types other than string.
+package format
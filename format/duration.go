@@ -0,0 +1,150 @@
+package format
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Duration is a length of time (the "duration" format), represented in JSON as an ISO 8601
+// duration (as specified in [RFC 3339 Appendix
+// A](https://tools.ietf.org/html/rfc3339#appendix-A)) such as "P1DT12H" or "PT0.5S".
+//
+// Durations with years or months are not supported because those units do not have a fixed
+// length. A day is always 24 hours and a week is always 7 days.
+type Duration time.Duration
+
+// ParseDuration parses an ISO 8601 duration. A leading "-" denotes a negative duration. Weeks
+// ("W") must be the only unit, as ISO 8601 specifies (such as "P2W", but not "P1W1D"). It returns
+// an error if the duration is not a whole number of nanoseconds or is out of the range of
+// time.Duration (about 292 years).
+func ParseDuration(s string) (Duration, error) {
+	invalid := func() (Duration, error) { return 0, fmt.Errorf("invalid duration %q", s) }
+
+	rest := s
+	negative := strings.HasPrefix(rest, "-")
+	rest = strings.TrimPrefix(rest, "-")
+	if !strings.HasPrefix(rest, "P") || rest == "P" {
+		return invalid()
+	}
+	rest = rest[1:]
+
+	var total big.Rat
+	var inTime bool
+	units := "WD" // the units allowed next (in order)
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime || len(rest) == 1 {
+				return invalid()
+			}
+			inTime, units, rest = true, "HMS", rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return invalid()
+		}
+		n, ok := new(big.Rat).SetString(rest[:i])
+		if !ok {
+			return invalid()
+		}
+		unit := rest[i]
+		j := strings.IndexByte(units, unit)
+		if j == -1 {
+			return invalid()
+		}
+		units = units[j+1:]
+		var d time.Duration
+		switch unit {
+		case 'W':
+			if len(rest) > i+1 {
+				return invalid() // weeks can't be combined with other units
+			}
+			d = 7 * 24 * time.Hour
+		case 'D':
+			d = 24 * time.Hour
+		case 'H':
+			d = time.Hour
+		case 'M':
+			d = time.Minute
+		case 'S':
+			d = time.Second
+		}
+		total.Add(&total, n.Mul(n, new(big.Rat).SetInt64(int64(d))))
+		rest = rest[i+1:]
+	}
+	if inTime && units == "HMS" {
+		return invalid() // "T" must be followed by at least one time unit
+	}
+
+	if negative {
+		total.Neg(&total)
+	}
+	if !total.IsInt() {
+		return 0, fmt.Errorf("duration %q is not a whole number of nanoseconds", s)
+	}
+	if !total.Num().IsInt64() {
+		return 0, fmt.Errorf("duration %q is out of range", s)
+	}
+	return Duration(total.Num().Int64()), nil
+}
+
+// String returns the duration in ISO 8601 format, using the units days, hours, minutes, and
+// seconds (such as "P1DT12H" or "PT0.5S").
+func (d Duration) String() string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var buf strings.Builder
+	// Use the magnitude as a uint64, because the magnitude of the smallest time.Duration is out of
+	// its range.
+	v := uint64(d)
+	if d < 0 {
+		buf.WriteByte('-')
+		v = -v
+	}
+	const (
+		day    = uint64(24 * time.Hour)
+		hour   = uint64(time.Hour)
+		minute = uint64(time.Minute)
+	)
+	buf.WriteByte('P')
+	if days := v / day; days > 0 {
+		fmt.Fprintf(&buf, "%dD", days)
+		v -= days * day
+	}
+	if v > 0 {
+		buf.WriteByte('T')
+		if hours := v / hour; hours > 0 {
+			fmt.Fprintf(&buf, "%dH", hours)
+			v -= hours * hour
+		}
+		if minutes := v / minute; minutes > 0 {
+			fmt.Fprintf(&buf, "%dM", minutes)
+			v -= minutes * minute
+		}
+		if v > 0 {
+			seconds := new(big.Rat).SetFrac64(int64(v), int64(time.Second))
+			s := strings.TrimRight(strings.TrimRight(seconds.FloatString(9), "0"), ".")
+			fmt.Fprintf(&buf, "%sS", s)
+		}
+	}
+	return buf.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
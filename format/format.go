@@ -0,0 +1,128 @@
+package format
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// URI is an absolute URI, which has a scheme (the "uri" format).
+type URI struct {
+	url.URL
+}
+
+// String returns the URI as a string.
+func (u URI) String() string {
+	return u.URL.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u URI) MarshalText() ([]byte, error) {
+	return []byte(u.URL.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It returns an error if the text is a relative
+// URI reference (such as "/a" or "#b").
+func (u *URI) UnmarshalText(text []byte) error {
+	parsed, err := url.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	if !parsed.IsAbs() {
+		return fmt.Errorf("invalid URI %q (must be absolute, with a scheme)", text)
+	}
+	u.URL = *parsed
+	return nil
+}
+
+// URIReference is a URI or a relative URI reference (the "uri-reference" format).
+type URIReference struct {
+	url.URL
+}
+
+// String returns the URI reference as a string.
+func (u URIReference) String() string {
+	return u.URL.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u URIReference) MarshalText() ([]byte, error) {
+	return []byte(u.URL.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *URIReference) UnmarshalText(text []byte) error {
+	parsed, err := url.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	u.URL = *parsed
+	return nil
+}
+
+// UUID is a universally unique identifier (the "uuid" format), as specified in [RFC
+// 4122](https://tools.ietf.org/html/rfc4122).
+type UUID [16]byte
+
+// ParseUUID parses a UUID in its canonical string form (such as
+// "f81d4fae-7dec-11d0-a765-00a0c91e6bf6").
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	src := []byte(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36])
+	if _, err := hex.Decode(u[:], src); err != nil {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// String returns the canonical (lowercase) string form of the UUID.
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Regexp is a compiled regular expression (the "regex" format).
+//
+// The "regex" format refers to ECMA 262 regular expressions. They are compiled using package
+// regexp, which does not support some ECMA 262 features (such as lookahead assertions).
+type Regexp struct {
+	*regexp.Regexp
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Regexp) MarshalText() ([]byte, error) {
+	if r.Regexp == nil {
+		return nil, errors.New("nil regular expression")
+	}
+	return []byte(r.Regexp.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Regexp) UnmarshalText(text []byte) error {
+	re, err := regexp.Compile(string(text))
+	if err != nil {
+		return err
+	}
+	r.Regexp = re
+	return nil
+}
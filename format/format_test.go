@@ -0,0 +1,92 @@
+package format
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := map[string]struct {
+		want    time.Duration
+		wantStr string // if different from the input
+	}{
+		"PT0S":         {want: 0},
+		"P0D":          {want: 0, wantStr: "PT0S"},
+		"PT1H":         {want: time.Hour},
+		"P1DT12H":      {want: 36 * time.Hour},
+		"P1W":          {want: 7 * 24 * time.Hour, wantStr: "P7D"},
+		"PT1M30.5S":    {want: 90*time.Second + 500*time.Millisecond},
+		"PT90M":        {want: 90 * time.Minute, wantStr: "PT1H30M"},
+		"P2DT3H4M5S":   {want: 2*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		"-PT0.000001S": {want: -time.Microsecond},
+
+		"PT9223372036.854775807S":  {want: math.MaxInt64, wantStr: "P106751DT23H47M16.854775807S"},
+		"-PT9223372036.854775808S": {want: math.MinInt64, wantStr: "-P106751DT23H47M16.854775808S"},
+	}
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			d, err := ParseDuration(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if time.Duration(d) != test.want {
+				t.Errorf("got %v, want %v", time.Duration(d), test.want)
+			}
+			wantStr := test.wantStr
+			if wantStr == "" {
+				wantStr = input
+			}
+			if d.String() != wantStr {
+				t.Errorf("got string %q, want %q", d.String(), wantStr)
+			}
+			if d2, err := ParseDuration(d.String()); err != nil || d2 != d {
+				t.Errorf("round trip of %q: got %v, %v, want %v", d.String(), time.Duration(d2), err, time.Duration(d))
+			}
+		})
+	}
+
+	for _, input := range []string{"", "P", "PT", "1D", "P1Y", "P1M", "PT1D", "P1H", "P1DT", "PT1S1M", "P1D1D", "PTS", "P-1D", "PT0.0000000001S", "PT9223372036.854775808S", "P106752D", "-P106752D", "P1W1D", "P1WT1H", "P1DW"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("%q: got nil error, want error", input)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type value struct {
+		URI      URI
+		Ref      URIReference
+		UUID     UUID
+		Regexp   Regexp
+		Duration Duration
+	}
+	const input = `{"URI":"https://example.com/a?b#c","Ref":"../a#b","UUID":"f81d4fae-7dec-11d0-a765-00a0c91e6bf6","Regexp":"^a+$","Duration":"PT1.5S"}`
+	var v value
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.URI.Host != "example.com" {
+		t.Errorf("got URI host %q, want %q", v.URI.Host, "example.com")
+	}
+	if !v.Regexp.MatchString("aaa") {
+		t.Error("got Regexp not matching")
+	}
+	if v.Duration != Duration(1500*time.Millisecond) {
+		t.Errorf("got Duration %v, want 1.5s", time.Duration(v.Duration))
+	}
+	output, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != input {
+		t.Errorf("got %s, want %s", output, input)
+	}
+
+	for _, input := range []string{`{"UUID":"f81d4fae7dec11d0a76500a0c91e6bf6"}`, `{"UUID":"g81d4fae-7dec-11d0-a765-00a0c91e6bf6"}`, `{"Regexp":"("}`, `{"Duration":"P1Y"}`, `{"URI":":"}`, `{"URI":"/a"}`, `{"URI":"example.com"}`, `{"Ref":":"}`} {
+		if err := json.Unmarshal([]byte(input), &v); err == nil {
+			t.Errorf("%s: got nil error, want error", input)
+		}
+	}
+}
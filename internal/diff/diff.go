@@ -0,0 +1,192 @@
+// Package diff computes line-based differences between texts and formats them as unified diffs.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown before and after each change.
+const contextLines = 3
+
+// Unified returns a unified diff (as produced by "diff -u") that transforms old into new, with
+// oldName and newName as the file names in its header. It returns "" if old and new are equal.
+func Unified(oldName, newName string, old, new []byte) string {
+	if string(old) == string(new) {
+		return ""
+	}
+	edits := lineEdits(splitLines(string(old)), splitLines(string(new)))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(edits); {
+		// Find the next change, and then the end of the hunk that contains it (which includes all
+		// changes separated by fewer than 2*contextLines unchanged lines).
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		end := start
+		for i := start; i < len(edits) && i-end <= 2*contextLines; i++ {
+			if edits[i].op != ' ' {
+				end = i + 1
+			}
+		}
+		first := max(start-contextLines, 0)
+		last := min(end+contextLines, len(edits))
+		writeHunk(&buf, edits[first:last])
+		start = end
+	}
+	return buf.String()
+}
+
+// writeHunk writes a hunk consisting of the edits (which include leading and trailing context).
+func writeHunk(buf *strings.Builder, edits []edit) {
+	var oldCount, newCount int
+	for _, e := range edits {
+		if e.op != '+' {
+			oldCount++
+		}
+		if e.op != '-' {
+			newCount++
+		}
+	}
+	fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(edits[0].oldLine, oldCount), hunkRange(edits[0].newLine, newCount))
+	for _, e := range edits {
+		buf.WriteByte(e.op)
+		buf.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the range of lines (with the given 0-based start line) in a hunk header.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		// An empty range refers to the line before the hunk.
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits text into lines, each of which includes its trailing newline (except for the
+// last line if the text doesn't end in a newline).
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// An edit is a line that is unchanged (op ' '), deleted from the old text (op '-'), or inserted
+// in the new text (op '+'). oldLine and newLine are the 0-based line numbers in the old and new
+// texts of the edit's position.
+type edit struct {
+	op               byte
+	line             string
+	oldLine, newLine int
+}
+
+// lineEdits returns the shortest sequence of edits that transforms the lines a into the lines b,
+// using the algorithm from "An O(ND) Difference Algorithm and Its Variations" (Myers, 1986).
+func lineEdits(a, b []string) []edit {
+	// Trim the common prefix and suffix, which are typically most of the lines.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []edit
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{op: ' ', line: a[i], oldLine: i, newLine: i})
+	}
+	for _, e := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		e.oldLine += prefix
+		e.newLine += prefix
+		edits = append(edits, e)
+	}
+	for i := suffix; i > 0; i-- {
+		edits = append(edits, edit{op: ' ', line: a[len(a)-i], oldLine: len(a) - i, newLine: len(b) - i})
+	}
+	return edits
+}
+
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	// v[offset+k] is the furthest x reached on diagonal k (where k = x - y). trace[d] is the part
+	// of v for diagonals -d..d before round d, for backtracking.
+	offset := n + m
+	v := make([]int, 2*(n+m)+2)
+	var trace [][]int
+	var d int
+search:
+	for d = 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // move down (insertion)
+			} else {
+				x = v[offset+k-1] + 1 // move right (deletion)
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backtrack from (n, m) to (0, 0) to recover the edits (in reverse order).
+	var edits []edit
+	x, y := n, m
+	for ; d >= 0; d-- {
+		k := x - y
+		var prevX, prevY int
+		if d > 0 {
+			prev := trace[d] // indexed by k+d
+			prevK := k - 1
+			if k == -d || (k != d && prev[k-1+d] < prev[k+1+d]) {
+				prevK = k + 1
+			}
+			prevX = prev[prevK+d]
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{op: ' ', line: a[x], oldLine: x, newLine: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{op: '+', line: b[prevY], oldLine: prevX, newLine: prevY})
+			} else {
+				edits = append(edits, edit{op: '-', line: a[prevX], oldLine: prevX, newLine: prevY})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
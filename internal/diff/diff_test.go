@@ -0,0 +1,162 @@
+package diff
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		want     string
+	}{
+		"equal": {
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		"separate hunks": {
+			old: "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n",
+			new: "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+			want: `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`,
+		},
+		"merged hunks": {
+			old: "a\nb\nc\nd\ne\nf\ng\nh\n",
+			new: "a\nB\nc\nd\ne\nf\nG\nh\n",
+			want: `--- old
++++ new
+@@ -1,8 +1,8 @@
+ a
+-b
++B
+ c
+ d
+ e
+ f
+-g
++G
+ h
+`,
+		},
+		"deletion": {
+			old: "a\nb\nc\n",
+			new: "a\nc\n",
+			want: `--- old
++++ new
+@@ -1,3 +1,2 @@
+ a
+-b
+ c
+`,
+		},
+		"from empty": {
+			old: "",
+			new: "x\n",
+			want: `--- old
++++ new
+@@ -0,0 +1 @@
++x
+`,
+		},
+		"no newline at end": {
+			old: "x\ny",
+			new: "x\ny\n",
+			want: `--- old
++++ new
+@@ -1,2 +1,2 @@
+ x
+-y
+\ No newline at end of file
++y
+`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Unified("old", "new", []byte(test.old), []byte(test.new)); got != test.want {
+				t.Errorf("got diff\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+// TestUnified_apply checks that applying the diffs of random texts to the old text yields the new
+// text.
+func TestUnified_apply(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	randomText := func() string {
+		lines := make([]string, rnd.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a'+rnd.Intn(4))) + "\n"
+		}
+		return strings.Join(lines, "")
+	}
+	for i := 0; i < 500; i++ {
+		old, new := randomText(), randomText()
+		diff := Unified("old", "new", []byte(old), []byte(new))
+		if got := apply(t, old, diff); got != new {
+			t.Fatalf("applying diff to %q: got %q, want %q\ndiff:\n%s", old, got, new, diff)
+		}
+	}
+}
+
+// apply applies the unified diff to old. It assumes that the diff is well formed and that old ends
+// in a newline.
+func apply(t *testing.T, old, diff string) string {
+	if diff == "" {
+		return old
+	}
+	oldLines := splitLines(old)
+	var out []string
+	next := 0 // the next line of oldLines to copy
+	for _, line := range splitLines(diff)[2:] {
+		switch line[0] {
+		case '@':
+			oldRange, _, _ := strings.Cut(strings.TrimPrefix(line, "@@ -"), " ")
+			startStr, countStr, hasCount := strings.Cut(oldRange, ",")
+			start, err := strconv.Atoi(startStr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count := 1
+			if hasCount {
+				if count, err = strconv.Atoi(countStr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if count > 0 {
+				start-- // 0-based
+			}
+			out = append(out, oldLines[next:start]...)
+			next = start
+		case ' ', '-':
+			if oldLines[next] != line[1:] {
+				t.Fatalf("diff context %q does not match line %d %q", line[1:], next, oldLines[next])
+			}
+			if line[0] == ' ' {
+				out = append(out, line[1:])
+			}
+			next++
+		case '+':
+			out = append(out, line[1:])
+		}
+	}
+	out = append(out, oldLines[next:]...)
+	return strings.Join(out, "")
+}
@@ -61,36 +61,51 @@ type TestCase struct {
 	Valid       bool
 }
 
-// Files returns all test files from the JSON Schema official test suite and this library's own test
-// suite.
+// Files returns all test files from the JSON Schema official test suite (for draft-07) and this
+// library's own test suite.
 func Files(internalDir string) (files []File, err error) {
 	officialTestSuiteDir := filepath.Join(internalDir, "jsonschematestsuite", "testdata", "official")
 	if _, err := os.Stat(filepath.Join(officialTestSuiteDir, "tests")); os.IsNotExist(err) {
 		return nil, fmt.Errorf("missing git submodule, run \"git submodule init && git submodule update\": %w", err)
 	}
-	for _, root := range []string{
-		filepath.Join(internalDir, "jsonschematestsuite", "testdata"),
-		filepath.Join(officialTestSuiteDir, "tests", "draft7"),
-	} {
-		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-			if path == officialTestSuiteDir {
-				return filepath.SkipDir
-			}
-			if err != nil {
-				return err
-			}
-			if !info.Mode().IsRegular() || filepath.Ext(info.Name()) != ".json" {
-				return nil
-			}
-			files = append(files, File{
-				Name: strings.TrimSuffix(strings.TrimPrefix(path, root+string(os.PathSeparator)), ".json"),
-				path: path,
-			})
-			return nil
-		})
+	ownFiles, err := walkFiles(filepath.Join(internalDir, "jsonschematestsuite", "testdata"), officialTestSuiteDir)
+	if err != nil {
+		return nil, err
+	}
+	officialFiles, err := OfficialFiles(internalDir, "draft7")
+	if err != nil {
+		return nil, err
+	}
+	return append(ownFiles, officialFiles...), nil
+}
+
+// OfficialFiles returns the test files for a draft from the JSON Schema official test suite. The
+// draft is the name of its directory in the test suite (such as "draft7" or "draft2020-12").
+func OfficialFiles(internalDir, draft string) ([]File, error) {
+	officialTestSuiteDir := filepath.Join(internalDir, "jsonschematestsuite", "testdata", "official")
+	if _, err := os.Stat(filepath.Join(officialTestSuiteDir, "tests")); os.IsNotExist(err) {
+		return nil, fmt.Errorf("missing git submodule, run \"git submodule init && git submodule update\": %w", err)
+	}
+	return walkFiles(filepath.Join(officialTestSuiteDir, "tests", draft), "")
+}
+
+// walkFiles returns the test files in root and its subdirectories (except skipDir).
+func walkFiles(root, skipDir string) (files []File, err error) {
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if path == skipDir {
+			return filepath.SkipDir
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
-	}
+		if !info.Mode().IsRegular() || filepath.Ext(info.Name()) != ".json" {
+			return nil
+		}
+		files = append(files, File{
+			Name: strings.TrimSuffix(strings.TrimPrefix(path, root+string(os.PathSeparator)), ".json"),
+			path: path,
+		})
+		return nil
+	})
 	return files, err
 }
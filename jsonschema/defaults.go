@@ -0,0 +1,196 @@
+package jsonschema
+
+import "strconv"
+
+// ApplyDefaults inserts the "default" value of each property that is missing from the decoded JSON
+// instance (as produced by encoding/json, such as a map[string]any) into the instance, and returns
+// the instance and the JSON Pointers of the values it inserted (in the order they were inserted).
+//
+// It walks the schema alongside the instance, following "$ref", "properties", "items" (and
+// "prefixItems"), and "allOf". If more than one of the schemas that apply to an object has a default
+// for a missing property, the first one found is used: the schema's own "properties" are consulted
+// before its "allOf" subschemas, which are consulted before its "$ref". The default of a property's
+// schema is taken from its "$ref" or "allOf" subschemas if it has none of its own. Defaults are
+// copied into the instance (so that later changes to the instance do not affect the schema), and
+// the defaults of the properties of an inserted value are applied to it as well (except for the
+// defaults of the schemas whose default is being inserted, which would otherwise nest forever in a
+// recursive schema).
+//
+// Objects in the instance are modified in place. The instance itself is never replaced, even if it
+// is nil and the schema has a default.
+func ApplyDefaults(schema *Schema, instance any) (any, []Pointer, error) {
+	refs, err := indexSchema(schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	a := &defaultsApplier{dialect: schema.Dialect(), refs: refs}
+	if err := a.apply(schema, instance, nil, nil); err != nil {
+		return nil, nil, err
+	}
+	return instance, a.filled, nil
+}
+
+// defaultsApplier applies the defaults of a root schema and its subschemas to an instance.
+type defaultsApplier struct {
+	dialect Dialect
+	refs    *schemaIndex
+	filled  []Pointer
+
+	// inserting holds the property schemas whose defaults are being inserted, while their
+	// defaults are applied to the inserted values.
+	inserting map[*Schema]bool
+}
+
+// apply applies the defaults of schema (and the subschemas it refers to) to instance, which is at
+// loc in the root instance. The active map holds the schemas already being applied to instance (to
+// avoid infinite recursion through $refs).
+func (a *defaultsApplier) apply(schema *Schema, instance any, loc Pointer, active map[*Schema]bool) error {
+	if schema == nil || schema.IsEmpty || schema.IsNegated || active[schema] {
+		return nil
+	}
+	if active == nil {
+		active = map[*Schema]bool{}
+	}
+	active[schema] = true
+	defer delete(active, schema)
+
+	refOverridesSiblings := schema.Reference != nil && a.dialect.refOverridesSiblings()
+	if !refOverridesSiblings {
+		switch instance := instance.(type) {
+		case map[string]any:
+			if schema.Properties != nil {
+				for _, name := range sortedKeys(*schema.Properties) {
+					if err := a.applyProperty((*schema.Properties)[name], instance, name, loc); err != nil {
+						return err
+					}
+				}
+			}
+		case []any:
+			for i, item := range instance {
+				if err := a.apply(itemSchema(schema, i), item, appendPointer(loc, strconv.Itoa(i)), nil); err != nil {
+					return err
+				}
+			}
+		}
+		for _, s := range schema.AllOf {
+			if err := a.apply(s, instance, loc, active); err != nil {
+				return err
+			}
+		}
+	}
+	if schema.Reference != nil {
+		target, err := a.refs.resolve(schema)
+		if err != nil {
+			return err
+		}
+		if err := a.apply(target, instance, loc, active); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyProperty inserts the default of the property's schema into object (which is at loc in the
+// root instance) if the property is missing, and then applies the property schema's defaults to the
+// property's value.
+func (a *defaultsApplier) applyProperty(schema *Schema, object map[string]any, name string, loc Pointer) error {
+	propLoc := appendPointer(loc, name)
+	if _, ok := object[name]; !ok {
+		if a.inserting[schema] {
+			return nil
+		}
+		value, ok, err := a.defaultOf(schema, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		object[name] = copyJSONValue(value)
+		a.filled = append(a.filled, propLoc)
+
+		if a.inserting == nil {
+			a.inserting = map[*Schema]bool{}
+		}
+		a.inserting[schema] = true
+		defer delete(a.inserting, schema)
+	}
+	return a.apply(schema, object[name], propLoc, nil)
+}
+
+// defaultOf returns the default of schema, or of the first of its "allOf" subschemas or its "$ref"
+// target that has a default. It reports whether a default was found.
+func (a *defaultsApplier) defaultOf(schema *Schema, seen map[*Schema]bool) (any, bool, error) {
+	if schema == nil || seen[schema] {
+		return nil, false, nil
+	}
+	if seen == nil {
+		seen = map[*Schema]bool{}
+	}
+	seen[schema] = true
+
+	refOverridesSiblings := schema.Reference != nil && a.dialect.refOverridesSiblings()
+	if !refOverridesSiblings {
+		if schema.Default != nil {
+			return *schema.Default, true, nil
+		}
+		for _, s := range schema.AllOf {
+			if value, ok, err := a.defaultOf(s, seen); ok || err != nil {
+				return value, ok, err
+			}
+		}
+	}
+	if schema.Reference != nil {
+		target, err := a.refs.resolve(schema)
+		if err != nil {
+			return nil, false, err
+		}
+		return a.defaultOf(target, seen)
+	}
+	return nil, false, nil
+}
+
+// itemSchema returns the schema that applies to the array item at index i according to schema's
+// "prefixItems", "items", and "additionalItems" keywords, or nil if there is none.
+func itemSchema(schema *Schema, i int) *Schema {
+	if i < len(schema.PrefixItems) {
+		return schema.PrefixItems[i]
+	}
+	if schema.Items == nil {
+		return nil
+	}
+	if schema.Items.Schema != nil {
+		return schema.Items.Schema
+	}
+	if i < len(schema.Items.Schemas) {
+		return schema.Items.Schemas[i]
+	}
+	return schema.AdditionalItems
+}
+
+// copyJSONValue returns a deep copy of the decoded JSON value v.
+func copyJSONValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		tmp := make(map[string]any, len(v))
+		for k, e := range v {
+			tmp[k] = copyJSONValue(e)
+		}
+		return tmp
+	case []any:
+		tmp := make([]any, len(v))
+		for i, e := range v {
+			tmp[i] = copyJSONValue(e)
+		}
+		return tmp
+	default:
+		return v
+	}
+}
+
+func appendPointer(p Pointer, token string) Pointer {
+	tmp := make(Pointer, len(p)+1)
+	copy(tmp, p)
+	tmp[len(p)] = token
+	return tmp
+}
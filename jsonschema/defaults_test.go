@@ -0,0 +1,164 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	tests := map[string]struct {
+		schema     string
+		instance   string
+		want       string
+		wantFilled []string
+	}{
+		"properties": {
+			schema:     `{"properties":{"a":{"default":1},"b":{"default":"x"},"c":{}}}`,
+			instance:   `{"b":"y"}`,
+			want:       `{"a":1,"b":"y"}`,
+			wantFilled: []string{"/a"},
+		},
+		"null is not missing": {
+			schema:   `{"properties":{"a":{"default":1}}}`,
+			instance: `{"a":null}`,
+			want:     `{"a":null}`,
+		},
+		"nested": {
+			schema:     `{"properties":{"a":{"properties":{"b":{"default":true}}}}}`,
+			instance:   `{"a":{}}`,
+			want:       `{"a":{"b":true}}`,
+			wantFilled: []string{"/a/b"},
+		},
+		"defaults in inserted default": {
+			schema:     `{"properties":{"a":{"default":{"x":1},"properties":{"y":{"default":2}}}}}`,
+			instance:   `{}`,
+			want:       `{"a":{"x":1,"y":2}}`,
+			wantFilled: []string{"/a", "/a/y"},
+		},
+		"items": {
+			schema:     `{"items":{"properties":{"a":{"default":0}}}}`,
+			instance:   `[{},{"a":1},"x"]`,
+			want:       `[{"a":0},{"a":1},"x"]`,
+			wantFilled: []string{"/0/a"},
+		},
+		"tuple items": {
+			schema:     `{"items":[{"properties":{"a":{"default":0}}}],"additionalItems":{"properties":{"b":{"default":1}}}}`,
+			instance:   `[{},{}]`,
+			want:       `[{"a":0},{"b":1}]`,
+			wantFilled: []string{"/0/a", "/1/b"},
+		},
+		"prefixItems": {
+			schema:     `{"$schema":"https://json-schema.org/draft/2020-12/schema","prefixItems":[{"properties":{"a":{"default":0}}}],"items":{"properties":{"b":{"default":1}}}}`,
+			instance:   `[{},{}]`,
+			want:       `[{"a":0},{"b":1}]`,
+			wantFilled: []string{"/0/a", "/1/b"},
+		},
+		"$ref": {
+			schema:     `{"definitions":{"d":{"properties":{"a":{"default":"x"}}}},"properties":{"p":{"$ref":"#/definitions/d"}}}`,
+			instance:   `{"p":{}}`,
+			want:       `{"p":{"a":"x"}}`,
+			wantFilled: []string{"/p/a"},
+		},
+		"default of $ref target": {
+			schema:     `{"definitions":{"d":{"default":3}},"properties":{"p":{"$ref":"#/definitions/d"}}}`,
+			instance:   `{}`,
+			want:       `{"p":3}`,
+			wantFilled: []string{"/p"},
+		},
+		"$ref with siblings (draft-07)": {
+			schema:     `{"definitions":{"d":{"properties":{"a":{"default":1}}}},"$ref":"#/definitions/d","properties":{"a":{"default":2},"b":{"default":2}}}`,
+			instance:   `{}`,
+			want:       `{"a":1}`,
+			wantFilled: []string{"/a"},
+		},
+		"$ref with siblings (2020-12)": {
+			schema:     `{"$schema":"https://json-schema.org/draft/2020-12/schema","$defs":{"d":{"properties":{"a":{"default":1},"c":{"default":1}}}},"$ref":"#/$defs/d","properties":{"a":{"default":2},"b":{"default":2}}}`,
+			instance:   `{}`,
+			want:       `{"a":2,"b":2,"c":1}`,
+			wantFilled: []string{"/a", "/b", "/c"},
+		},
+		"allOf": {
+			schema:     `{"properties":{"a":{"default":1}},"allOf":[{"properties":{"a":{"default":2},"b":{"default":2}}},{"properties":{"b":{"default":3},"c":{"default":3}}}]}`,
+			instance:   `{}`,
+			want:       `{"a":1,"b":2,"c":3}`,
+			wantFilled: []string{"/a", "/b", "/c"},
+		},
+		"recursive $ref": {
+			schema:     `{"properties":{"a":{"default":0},"child":{"$ref":"#"}},"allOf":[{"$ref":"#"}]}`,
+			instance:   `{"child":{"child":{}}}`,
+			want:       `{"a":0,"child":{"a":0,"child":{"a":0}}}`,
+			wantFilled: []string{"/a", "/child/a", "/child/child/a"},
+		},
+		"recursive $ref with default": {
+			schema:     `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"child":{"$ref":"#","default":{}}}}`,
+			instance:   `{}`,
+			want:       `{"child":{}}`,
+			wantFilled: []string{"/child"},
+		},
+		"escaped pointer": {
+			schema:     `{"properties":{"a/b":{"default":1}}}`,
+			instance:   `{}`,
+			want:       `{"a/b":1}`,
+			wantFilled: []string{"/a~1b"},
+		},
+		"not an object": {
+			schema:   `{"properties":{"a":{"default":1}}}`,
+			instance: `"x"`,
+			want:     `"x"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema *Schema
+			if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			var instance any
+			if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			got, filled, err := ApplyDefaults(schema, instance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data, err := json.Marshal(got); err != nil {
+				t.Fatal(err)
+			} else if string(data) != test.want {
+				t.Errorf("got %s, want %s", data, test.want)
+			}
+			var gotFilled []string
+			for _, p := range filled {
+				gotFilled = append(gotFilled, p.String())
+			}
+			if !reflect.DeepEqual(gotFilled, test.wantFilled) {
+				t.Errorf("got filled %q, want %q", gotFilled, test.wantFilled)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_copiesDefaults(t *testing.T) {
+	var schema *Schema
+	if err := json.Unmarshal([]byte(`{"properties":{"a":{"default":{"b":[1]}}}}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	instance, _, err := ApplyDefaults(schema, map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance.(map[string]any)["a"].(map[string]any)["b"].([]any)[0] = 2.0
+	if want := map[string]any{"b": []any{1.0}}; !reflect.DeepEqual(*(*schema.Properties)["a"].Default, want) {
+		t.Errorf("schema default was modified: got %v, want %v", *(*schema.Properties)["a"].Default, want)
+	}
+}
+
+func TestApplyDefaults_unresolvableRef(t *testing.T) {
+	var schema *Schema
+	if err := json.Unmarshal([]byte(`{"properties":{"a":{"$ref":"#/definitions/missing"}}}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ApplyDefaults(schema, map[string]any{}); err == nil {
+		t.Fatal("got nil error, want error")
+	}
+}
@@ -0,0 +1,42 @@
+package jsonschema
+
+import "strings"
+
+// A Dialect is a version (draft) of JSON Schema, identified by the URI of its meta-schema.
+type Dialect string
+
+const (
+	Draft07     Dialect = "http://json-schema.org/draft-07/schema#"
+	Draft201909 Dialect = "https://json-schema.org/draft/2019-09/schema"
+	Draft202012 Dialect = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// ParseDialect returns the dialect whose meta-schema URI is uri (as given in a "$schema" keyword).
+// The URI's scheme may be http or https, and an empty fragment is ignored.
+func ParseDialect(uri string) (Dialect, bool) {
+	uri = strings.TrimSuffix(uri, "#")
+	uri = strings.TrimPrefix(strings.TrimPrefix(uri, "http://"), "https://")
+	for _, d := range []Dialect{Draft07, Draft201909, Draft202012} {
+		if strings.TrimPrefix(strings.TrimPrefix(strings.TrimSuffix(string(d), "#"), "http://"), "https://") == uri {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// Dialect returns the dialect of the schema declared by its "$schema" keyword. If the schema has
+// no "$schema" keyword (or it refers to an unknown meta-schema), it returns Draft07.
+func (s *Schema) Dialect() Dialect {
+	if s.SchemaRef != nil {
+		if d, ok := ParseDialect(*s.SchemaRef); ok {
+			return d
+		}
+	}
+	return Draft07
+}
+
+// refOverridesSiblings reports whether the other keywords of a schema with a "$ref" are ignored,
+// which is the case before draft 2019-09.
+func (d Dialect) refOverridesSiblings() bool {
+	return d == Draft07
+}
@@ -1,5 +1,7 @@
-// Package jsonschema reads and describes JSON Schema documents.
+// Package jsonschema reads and describes JSON Schema documents, and validates JSON documents
+// against them.
 //
 // Compatible with JSON Schema draft-07 as specified in
-// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01).
+// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01). The
+// commonly used keywords of drafts 2019-09 and 2020-12 are also supported (see Dialect).
 package jsonschema
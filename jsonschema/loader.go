@@ -0,0 +1,234 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Loader loads JSON Schema documents by URI, for resolving $refs to other documents.
+type Loader interface {
+	// Load returns the JSON Schema document identified by the absolute URI (which has no
+	// fragment), with its RetrievalURI set to the URI.
+	Load(uri *url.URL) (*Schema, error)
+}
+
+// Load loads the JSON Schema document at uri using loader.
+//
+// The document's RetrievalURI is uri, so that the $refs in the document are resolved relative to the
+// URI it was loaded from (unless it has an absolute "$id"). Its "$id" is left as is. If the loader
+// did not set the RetrievalURI, a copy of the document with the RetrievalURI set is returned (so
+// that a document shared by the loader is not modified).
+func Load(loader Loader, uri string) (*Schema, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URI of JSON Schema document: %w", err)
+	}
+	u.Fragment, u.RawFragment = "", ""
+	schema, err := loader.Load(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JSON Schema document %q: %w", u, err)
+	}
+	return withRetrievalURI(schema, u), nil
+}
+
+// withRetrievalURI returns schema if its RetrievalURI is uri, and otherwise a (shallow) copy of
+// schema with its RetrievalURI set to uri.
+func withRetrievalURI(schema *Schema, uri *url.URL) *Schema {
+	if schema.RetrievalURI == uri.String() {
+		return schema
+	}
+	tmp := *schema
+	tmp.RetrievalURI = uri.String()
+	return &tmp
+}
+
+// BaseURI returns the base URI of the root schema document: its "$id" (resolved against its
+// RetrievalURI, if relative) or, if it has no "$id", its RetrievalURI. It returns "" if it has
+// neither.
+func (s *Schema) BaseURI() string {
+	if s.ID == nil {
+		return s.RetrievalURI
+	}
+	if s.RetrievalURI != "" {
+		base, err := url.Parse(s.RetrievalURI)
+		if err == nil {
+			if id, err := url.Parse(*s.ID); err == nil {
+				return base.ResolveReference(id).String()
+			}
+		}
+	}
+	return *s.ID
+}
+
+// LoadReferences loads (using loader) the documents that are referred to by $refs in the schemas
+// but are not among the schemas, and then (recursively) the documents that those documents refer
+// to. It returns the loaded documents. Each document is loaded only once, even if documents refer
+// to each other.
+//
+// References to the JSON Schema meta-schemas are not loaded.
+func LoadReferences(loader Loader, schemas []*Schema) ([]*Schema, error) {
+	known := map[string]bool{} // URIs of the documents that are available (without fragments)
+	var indexes []*schemaIndex
+	add := func(schema *Schema) error {
+		index, err := indexSchema(schema)
+		if err != nil {
+			return err
+		}
+		for key := range index.byURI {
+			doc, _, _ := strings.Cut(key, "#")
+			known[doc] = true
+		}
+		indexes = append(indexes, index)
+		return nil
+	}
+	for _, schema := range schemas {
+		if err := add(schema); err != nil {
+			return nil, err
+		}
+	}
+
+	var loaded []*Schema
+	for i := 0; i < len(indexes); i++ { // indexes grows as documents are loaded
+		var docs []string
+		for schema, base := range indexes[i].baseOf {
+			for _, reference := range []*string{schema.Reference, schema.DynamicRef} {
+				if reference == nil {
+					continue
+				}
+				ref, err := url.Parse(*reference)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse $ref: %w", err)
+				}
+				ref = base.ResolveReference(ref)
+				ref.Fragment, ref.RawFragment = "", ""
+				doc := ref.String()
+				if doc == "" || known[doc] {
+					continue
+				}
+				if _, ok := ParseDialect(doc); ok {
+					continue // a meta-schema
+				}
+				if !ref.IsAbs() {
+					return nil, fmt.Errorf("unable to load $ref %q (the referring document has no absolute base URI)", *reference)
+				}
+				known[doc] = true
+				docs = append(docs, doc)
+			}
+		}
+		sort.Strings(docs) // load in a deterministic order
+		for _, doc := range docs {
+			schema, err := Load(loader, doc)
+			if err != nil {
+				return nil, err
+			}
+			loaded = append(loaded, schema)
+			if err := add(schema); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return loaded, nil
+}
+
+// FileLoader loads JSON Schema documents with "file" URIs from the local filesystem.
+type FileLoader struct{}
+
+// Load implements Loader.
+func (FileLoader) Load(uri *url.URL) (*Schema, error) {
+	if uri.Scheme != "file" {
+		return nil, fmt.Errorf("unsupported URI scheme %q (only \"file\" is supported)", uri.Scheme)
+	}
+	data, err := os.ReadFile(uri.Path)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalSchema(data, uri)
+}
+
+// FSLoader loads JSON Schema documents from a file system. A document whose URI starts with Base
+// (such as "https://example.com/schemas/") is loaded from the file in FS whose path is the rest
+// of the URI.
+type FSLoader struct {
+	FS   fs.FS
+	Base string
+}
+
+// Load implements Loader.
+func (l FSLoader) Load(uri *url.URL) (*Schema, error) {
+	name, ok := strings.CutPrefix(uri.String(), l.Base)
+	if !ok {
+		return nil, fmt.Errorf("URI is not under the base URI %q: %w", l.Base, fs.ErrNotExist)
+	}
+	data, err := fs.ReadFile(l.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalSchema(data, uri)
+}
+
+// MapLoader loads JSON Schema documents from memory. The map key is the document's URI. The
+// documents in the map are not modified; Load returns a copy of a document if its RetrievalURI is
+// not its key.
+type MapLoader map[string]*Schema
+
+// Load implements Loader.
+func (l MapLoader) Load(uri *url.URL) (*Schema, error) {
+	schema, ok := l[uri.String()]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return withRetrievalURI(schema, uri), nil
+}
+
+// NewCachingLoader returns a Loader that loads each document (using loader) only once. Later loads
+// of the same URI return the same *Schema (or error). It is safe for concurrent use.
+func NewCachingLoader(loader Loader) Loader {
+	return &cachingLoader{loader: loader, cache: map[string]cachedLoad{}}
+}
+
+type cachingLoader struct {
+	loader Loader
+
+	mu    sync.Mutex
+	cache map[string]cachedLoad
+}
+
+type cachedLoad struct {
+	schema *Schema
+	err    error
+}
+
+// Load implements Loader.
+func (l *cachingLoader) Load(uri *url.URL) (*Schema, error) {
+	key := uri.String()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok := l.cache[key]; ok {
+		return c.schema, c.err
+	}
+	schema, err := l.loader.Load(uri)
+	if schema != nil {
+		schema = withRetrievalURI(schema, uri)
+	}
+	l.cache[key] = cachedLoad{schema: schema, err: err}
+	return schema, err
+}
+
+// unmarshalSchema unmarshals the JSON Schema document that was loaded from uri.
+func unmarshalSchema(data []byte, uri *url.URL) (*Schema, error) {
+	var schema *Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("JSON Schema document is null")
+	}
+	schema.RetrievalURI = uri.String()
+	return schema, nil
+}
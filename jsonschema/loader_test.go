@@ -0,0 +1,141 @@
+package jsonschema
+
+import (
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadReferences(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.json":     {Data: []byte(`{"properties":{"b":{"$ref":"b.json#/definitions/b"}}}`)},
+		"b.json":     {Data: []byte(`{"definitions":{"b":{"items":{"$ref":"a.json"}}},"properties":{"c":{"$ref":"sub/c.json"}}}`)},
+		"sub/c.json": {Data: []byte(`{"$id":"c-id.json","allOf":[{"$ref":"http://json-schema.org/draft-07/schema#"}]}`)},
+	}
+	loader := &countingLoader{Loader: FSLoader{FS: fsys, Base: "https://example.com/"}, counts: map[string]int{}}
+
+	root, err := Load(loader, "https://example.com/a.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.ID != nil {
+		t.Errorf("got $id %q, want none", *root.ID)
+	}
+	if want := "https://example.com/a.json"; root.BaseURI() != want {
+		t.Errorf("got base URI %q, want %q", root.BaseURI(), want)
+	}
+	loaded, err := LoadReferences(loader, []*Schema{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range loaded {
+		ids = append(ids, s.BaseURI())
+	}
+	if want := []string{"https://example.com/b.json", "https://example.com/sub/c-id.json"}; len(ids) != len(want) || ids[0] != want[0] || ids[1] != want[1] {
+		t.Errorf("got loaded documents %q, want %q", ids, want)
+	}
+	for uri, n := range loader.counts {
+		if n != 1 {
+			t.Errorf("%s: loaded %d times, want 1", uri, n)
+		}
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		root := &Schema{ID: strptr("https://example.com/x.json"), AllOf: []*Schema{{Reference: strptr("missing.json")}}}
+		if _, err := LoadReferences(loader, []*Schema{root}); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("got error %v, want fs.ErrNotExist", err)
+		}
+	})
+	t.Run("no base URI", func(t *testing.T) {
+		root := &Schema{Reference: strptr("b.json")}
+		if _, err := LoadReferences(loader, []*Schema{root}); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
+
+func TestFileLoader(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"title":"a"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := FileLoader{}.Load(&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "a.json"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Title == nil || *schema.Title != "a" {
+		t.Errorf("got title %v, want %q", schema.Title, "a")
+	}
+	if _, err := (FileLoader{}).Load(&url.URL{Scheme: "https", Host: "example.com"}); err == nil {
+		t.Error("got nil error for https URI, want error")
+	}
+}
+
+func TestCachingLoader(t *testing.T) {
+	counting := &countingLoader{Loader: MapLoader{"https://example.com/a.json": &Schema{}}, counts: map[string]int{}}
+	loader := NewCachingLoader(counting)
+	u := &url.URL{Scheme: "https", Host: "example.com", Path: "/a.json"}
+	a1, err := loader.Load(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, err := loader.Load(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a1 != a2 {
+		t.Error("got different schemas for the same URI")
+	}
+	if n := counting.counts[u.String()]; n != 1 {
+		t.Errorf("got %d loads, want 1", n)
+	}
+}
+
+type countingLoader struct {
+	Loader
+	counts map[string]int
+}
+
+func (l *countingLoader) Load(uri *url.URL) (*Schema, error) {
+	l.counts[uri.String()]++
+	return l.Loader.Load(uri)
+}
+
+func TestLoad_retrievalURI(t *testing.T) {
+	a := &Schema{}
+	loader := NewCachingLoader(MapLoader{"https://example.com/a.json": a})
+
+	var wg sync.WaitGroup
+	schemas := make([]*Schema, 2)
+	for i := range schemas {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			schema, err := Load(loader, "https://example.com/a.json#/definitions/b")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			schemas[i] = schema
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+	if schemas[0] != schemas[1] {
+		t.Error("got different schemas for the same URI")
+	}
+	if want := "https://example.com/a.json"; schemas[0].RetrievalURI != want {
+		t.Errorf("got RetrievalURI %q, want %q", schemas[0].RetrievalURI, want)
+	}
+	if a.RetrievalURI != "" {
+		t.Errorf("MapLoader's schema was modified: got RetrievalURI %q, want empty", a.RetrievalURI)
+	}
+}
@@ -0,0 +1,236 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// A Pointer is a JSON Pointer (as specified in [RFC 6901](https://tools.ietf.org/html/rfc6901)),
+// which identifies a value in a JSON document. Each element is an unescaped reference token.
+//
+// The empty (or nil) Pointer refers to the whole document.
+type Pointer []string
+
+// ParsePointer parses a JSON Pointer string (such as "/definitions/a~1b"), unescaping "~1" to "/"
+// and "~0" to "~" in each reference token.
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON Pointer %q (must be empty or start with \"/\")", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid JSON Pointer %q (\"~\" must be followed by \"0\" or \"1\")", s)
+			}
+		}
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return Pointer(tokens), nil
+}
+
+// ParsePointerURIFragment parses a JSON Pointer from its URI fragment representation (such as
+// "#/definitions/a%20b", with or without the leading "#"), which is percent-encoded.
+func ParsePointerURIFragment(fragment string) (Pointer, error) {
+	s, err := url.PathUnescape(strings.TrimPrefix(fragment, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON Pointer URI fragment %q: %w", fragment, err)
+	}
+	return ParsePointer(s)
+}
+
+// PointerFromReferenceTokens returns the JSON Pointer for the reference tokens.
+func PointerFromReferenceTokens(tokens []ReferenceToken) Pointer {
+	p := make(Pointer, len(tokens))
+	for i, token := range tokens {
+		if token.Name != "" {
+			p[i] = token.Name
+		} else {
+			p[i] = strconv.Itoa(token.Index)
+		}
+	}
+	return p
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// String returns the JSON Pointer string, with "~" and "/" in reference tokens escaped.
+func (p Pointer) String() string {
+	var buf strings.Builder
+	for _, token := range p {
+		buf.WriteByte('/')
+		buf.WriteString(pointerEscaper.Replace(token))
+	}
+	return buf.String()
+}
+
+// URIFragment returns the URI fragment representation of the JSON Pointer (without the leading
+// "#"), percent-encoding the characters that are not allowed in a URI fragment.
+func (p Pointer) URIFragment() string {
+	return (&url.URL{Fragment: p.String()}).EscapedFragment()
+}
+
+// Equal reports whether p and other consist of the same reference tokens.
+func (p Pointer) Equal(other Pointer) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// errPointerNotFound is returned when a JSON Pointer refers to a value that doesn't exist.
+var errPointerNotFound = errors.New("JSON Pointer refers to a nonexistent value")
+
+// EvaluateJSON returns the value in the JSON document data that p refers to.
+func (p Pointer) EvaluateJSON(data []byte) (json.RawMessage, error) {
+	value := json.RawMessage(bytes.TrimSpace(data))
+	for i, token := range p {
+		if len(value) == 0 {
+			return nil, errors.New("empty JSON document")
+		}
+		var ok bool
+		switch value[0] {
+		case '{':
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(value, &object); err != nil {
+				return nil, err
+			}
+			value, ok = object[token]
+		case '[':
+			var array []json.RawMessage
+			if err := json.Unmarshal(value, &array); err != nil {
+				return nil, err
+			}
+			if index, isIndex := parseArrayIndex(token); isIndex && index < len(array) {
+				value, ok = array[index], true
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errPointerNotFound, p[:i+1])
+		}
+		value = bytes.TrimSpace(value)
+	}
+	return value, nil
+}
+
+// EvaluateSchema returns the (sub)schema of schema that p refers to.
+//
+// Subschemas are located by following the JSON Schema keywords that contain subschemas (such as
+// "properties" and "items"). If p refers to some other location (such as a schema in an extension
+// keyword), the location is found in the schema's Raw JSON and a new Schema is unmarshaled from it.
+func (p Pointer) EvaluateSchema(schema *Schema) (*Schema, error) {
+	for i := 0; i < len(p); {
+		next, n := subschema(schema, p[i:])
+		if n == 0 {
+			if schema.Raw == nil {
+				return nil, fmt.Errorf("%w: %s", errPointerNotFound, p[:i+1])
+			}
+			data, err := p[i:].EvaluateJSON(*schema.Raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", errPointerNotFound, p)
+			}
+			var s Schema
+			if err := json.Unmarshal(data, &s); err != nil {
+				return nil, fmt.Errorf("JSON Pointer %s does not refer to a JSON Schema: %w", p, err)
+			}
+			return &s, nil
+		}
+		schema = next
+		i += n
+	}
+	return schema, nil
+}
+
+// subschema returns the subschema of schema that the first 1 or 2 reference tokens refer to, and
+// the number of tokens consumed. It returns 0 if the tokens do not refer to a subschema in one of
+// the Schema type's fields.
+func subschema(schema *Schema, tokens []string) (*Schema, int) {
+	single := map[string]*Schema{
+		"additionalItems":       schema.AdditionalItems,
+		"additionalProperties":  schema.AdditionalProperties,
+		"contains":              schema.Contains,
+		"else":                  schema.Else,
+		"if":                    schema.If,
+		"not":                   schema.Not,
+		"propertyNames":         schema.PropertyNames,
+		"then":                  schema.Then,
+		"unevaluatedItems":      schema.UnevaluatedItems,
+		"unevaluatedProperties": schema.UnevaluatedProperties,
+	}
+	if schema.Items != nil {
+		single["items"] = schema.Items.Schema
+	}
+	if s := single[tokens[0]]; s != nil {
+		return s, 1
+	}
+	if len(tokens) < 2 {
+		return nil, 0
+	}
+
+	maps := map[string]*map[string]*Schema{
+		"$defs":             schema.Defs,
+		"definitions":       schema.Definitions,
+		"dependentSchemas":  schema.DependentSchemas,
+		"patternProperties": schema.PatternProperties,
+		"properties":        schema.Properties,
+	}
+	if m := maps[tokens[0]]; m != nil {
+		if s := (*m)[tokens[1]]; s != nil {
+			return s, 2
+		}
+		return nil, 0
+	}
+	if tokens[0] == "dependencies" && schema.Dependencies != nil {
+		if d := (*schema.Dependencies)[tokens[1]]; d != nil && d.Schema != nil {
+			return d.Schema, 2
+		}
+		return nil, 0
+	}
+
+	lists := map[string][]*Schema{
+		"allOf":       schema.AllOf,
+		"anyOf":       schema.AnyOf,
+		"oneOf":       schema.OneOf,
+		"prefixItems": schema.PrefixItems,
+	}
+	if schema.Items != nil {
+		lists["items"] = schema.Items.Schemas
+	}
+	if list := lists[tokens[0]]; list != nil {
+		if i, ok := parseArrayIndex(tokens[1]); ok && i < len(list) && list[i] != nil {
+			return list[i], 2
+		}
+	}
+	return nil, 0
+}
+
+// parseArrayIndex parses a JSON Pointer reference token that refers to an array element. Leading
+// zeros are not allowed.
+func parseArrayIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	i, err := strconv.Atoi(token)
+	return i, err == nil
+}
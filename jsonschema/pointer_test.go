@@ -0,0 +1,142 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := map[string]Pointer{
+		"":          {},
+		"/":         {""},
+		"/a/b":      {"a", "b"},
+		"/a~1b/c~0": {"a/b", "c~"},
+		"/~01":      {"~1"},
+		"//":        {"", ""},
+		"/a%20b":    {"a%20b"},
+	}
+	for s, want := range tests {
+		t.Run(s, func(t *testing.T) {
+			p, err := ParsePointer(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.Equal(want) {
+				t.Errorf("got %q, want %q", p, want)
+			}
+			if p.String() != s {
+				t.Errorf("got string %q, want %q", p.String(), s)
+			}
+		})
+	}
+
+	for _, s := range []string{"a", "/~", "/~2", "/a~"} {
+		if _, err := ParsePointer(s); err == nil {
+			t.Errorf("%q: got nil error, want error", s)
+		}
+	}
+}
+
+func TestParsePointerURIFragment(t *testing.T) {
+	p, err := ParsePointerURIFragment("#/a%20b/c~1d/%25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Pointer{"a b", "c/d", "%"}); !p.Equal(want) {
+		t.Errorf("got %q, want %q", p, want)
+	}
+	if want := "/a%20b/c~1d/%25"; p.URIFragment() != want {
+		t.Errorf("got URI fragment %q, want %q", p.URIFragment(), want)
+	}
+}
+
+func TestPointer_EvaluateJSON(t *testing.T) {
+	// The example from https://tools.ietf.org/html/rfc6901#section-5.
+	const doc = `{"foo":["bar","baz"],"":0,"a/b":1,"c%d":2,"e^f":3,"g|h":4,"i\\j":5,"k\"l":6," ":7,"m~n":8}`
+	tests := map[string]string{
+		"":       doc,
+		"/foo":   `["bar","baz"]`,
+		"/foo/0": `"bar"`,
+		"/":      `0`,
+		"/a~1b":  `1`,
+		"/c%d":   `2`,
+		"/e^f":   `3`,
+		"/g|h":   `4`,
+		"/i\\j":  `5`,
+		"/k\"l":  `6`,
+		"/ ":     `7`,
+		"/m~0n":  `8`,
+	}
+	for s, want := range tests {
+		t.Run(s, func(t *testing.T) {
+			p, err := ParsePointer(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.EvaluateJSON([]byte(doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+
+	for _, s := range []string{"/x", "/foo/2", "/foo/01", "/foo/-", "/foo/0/x"} {
+		p, _ := ParsePointer(s)
+		if _, err := p.EvaluateJSON([]byte(doc)); err == nil {
+			t.Errorf("%q: got nil error, want error", s)
+		}
+	}
+}
+
+func TestPointer_EvaluateSchema(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+		"definitions": {"a/b": {"title": "ab"}, "": {"$defs": {"": {"title": "empty"}}}},
+		"properties": {"p": {"items": [{"title": "item0"}]}},
+		"allOf": [{"title": "allOf0"}],
+		"x-extension": {"schema": {"title": "extension"}}
+	}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]*Schema{
+		"":                      &schema,
+		"/definitions/a~1b":     (*schema.Definitions)["a/b"],
+		"/definitions//$defs/":  (*(*schema.Definitions)[""].Defs)[""],
+		"/properties/p/items/0": (*schema.Properties)["p"].Items.Schemas[0],
+		"/allOf/0":              schema.AllOf[0],
+	}
+	for s, want := range tests {
+		t.Run(s, func(t *testing.T) {
+			p, err := ParsePointer(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.EvaluateSchema(&schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("extension keyword", func(t *testing.T) {
+		got, err := Pointer{"x-extension", "schema"}.EvaluateSchema(&schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Title == nil || *got.Title != "extension" {
+			t.Errorf("got %+v, want schema with title %q", got, "extension")
+		}
+	})
+	t.Run("nonexistent", func(t *testing.T) {
+		if _, err := (Pointer{"definitions", "x"}).EvaluateSchema(&schema); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
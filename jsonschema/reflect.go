@@ -0,0 +1,443 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reflect returns a draft-07 JSON Schema that describes the JSON encoding (by encoding/json) of
+// values of v's Go type.
+//
+// A Go struct type is represented by an object schema whose properties are its fields, named by
+// their "json" struct tags (following the rules of encoding/json). A property is required unless
+// its field is a pointer or has the "omitempty" option, and the property allows null if its field
+// is a pointer, slice, or map without the "omitempty" option. A number or boolean field with the
+// "string" option is represented by a string schema (because encoding/json encodes its value in a
+// JSON string). The fields of an embedded struct type are promoted (as they are by encoding/json)
+// by an allOf branch that refers to the embedded type's schema. Maps with string keys are
+// represented by object schemas (with additionalProperties), and slices and arrays by array
+// schemas. Values of types that encoding/json can't encode (such as channels and functions) are
+// represented by the false schema, and struct fields of those types are omitted.
+//
+// The schema of each Go named struct type (other than v's type, whose schema is the root schema) is
+// in "definitions" and is referred to by "$ref". The title of the root schema is the name of v's
+// type.
+//
+// A struct field's "jsonschema" struct tag sets keywords of the property's schema. It is a
+// comma-separated list of key=value pairs (with commas in values escaped as "\\," in the struct
+// tag), such as `jsonschema:"description=The port to listen on,minimum=1,maximum=65535"`. The keys
+// are title, description, format, pattern, enum (with values separated by "|"), default, minimum,
+// maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength, minItems,
+// maxItems, uniqueItems, minProperties, and maxProperties. The key "required" (with no value) makes
+// the property required even if the field is a pointer or has the "omitempty" option. Reflect
+// panics if a "jsonschema" struct tag is invalid.
+func Reflect(v any) *Schema {
+	t := reflect.TypeOf(v)
+	r := reflector{root: derefType(t), names: map[reflect.Type]string{}, definitions: map[string]*Schema{}}
+	var schema *Schema
+	if r.root != nil && r.root.Kind() == reflect.Struct && r.root.Name() != "" && !isSpecialType(r.root) {
+		schema = r.structSchema(r.root)
+		title := r.root.Name()
+		schema.Title = &title
+	} else {
+		schema = r.schema(t)
+	}
+	dialect := string(Draft07)
+	schema.SchemaRef = &dialect
+	if len(r.definitions) > 0 {
+		schema.Definitions = &r.definitions
+	}
+	return schema
+}
+
+// reflector builds JSON Schemas from Go types (see Reflect).
+type reflector struct {
+	root        reflect.Type            // the type whose schema is the root schema
+	names       map[reflect.Type]string // the name of each Go named struct type's definition
+	definitions map[string]*Schema
+}
+
+// schema returns the schema for values of the Go type t.
+func (r *reflector) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{IsEmpty: true} // the type of a nil interface value
+	}
+	t = derefType(t)
+
+	switch {
+	case t == timeType:
+		format := Format("date-time")
+		return &Schema{Type: PrimitiveTypeList{StringType}, Format: &format}
+	case implements(t, jsonMarshalerType):
+		return &Schema{IsEmpty: true} // the JSON encoding is unknown
+	case implements(t, textMarshalerType):
+		return &Schema{Type: PrimitiveTypeList{StringType}}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: PrimitiveTypeList{BooleanType}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return integerSchema(t.Kind(), false)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return integerSchema(t.Kind(), true)
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: PrimitiveTypeList{NumberType}}
+	case reflect.String:
+		return &Schema{Type: PrimitiveTypeList{StringType}}
+	case reflect.Interface:
+		return &Schema{IsEmpty: true}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && !implements(t.Elem(), jsonMarshalerType) && !implements(t.Elem(), textMarshalerType) {
+			return &Schema{Type: PrimitiveTypeList{StringType}} // encoding/json encodes []byte as a base64 string
+		}
+		schema := &Schema{
+			Type:  PrimitiveTypeList{ArrayType},
+			Items: &SchemaOrSchemaList{Schema: r.schema(t.Elem())},
+		}
+		if t.Kind() == reflect.Array {
+			n := int64(t.Len())
+			schema.MinItems, schema.MaxItems = &n, &n
+		}
+		return schema
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !implements(t.Key(), textMarshalerType) {
+				return &Schema{IsNegated: true}
+			}
+		}
+		return &Schema{
+			Type:                 PrimitiveTypeList{ObjectType},
+			AdditionalProperties: r.schema(t.Elem()),
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return r.ref(t)
+	}
+	return &Schema{IsNegated: true} // encoding/json can't encode values of this type
+}
+
+// ref returns a $ref to the schema for the Go named struct type t (adding it to the definitions if
+// needed).
+func (r *reflector) ref(t reflect.Type) *Schema {
+	if t == r.root {
+		ref := "#"
+		return &Schema{Reference: &ref}
+	}
+	name, ok := r.names[t]
+	if !ok {
+		name = r.definitionName(t)
+		r.names[t] = name
+		r.definitions[name] = nil // reserve the name before (possibly recursively) reflecting t
+		r.definitions[name] = r.structSchema(t)
+	}
+	ref := "#" + Pointer{"definitions", name}.URIFragment()
+	return &Schema{Reference: &ref}
+}
+
+// definitionName returns a name (that is not yet used) for the definition of the schema for the Go
+// named type t: its name, or if that is used, its name qualified by its package name.
+func (r *reflector) definitionName(t reflect.Type) string {
+	name := t.Name()
+	if _, ok := r.definitions[name]; !ok && (r.root == nil || name != r.root.Name()) {
+		return name
+	}
+	qualified := path.Base(t.PkgPath()) + "." + name
+	name = qualified
+	for i := 2; ; i++ {
+		if _, ok := r.definitions[name]; !ok {
+			return name
+		}
+		name = qualified + strconv.Itoa(i)
+	}
+}
+
+// structSchema returns the object schema for the Go struct type t.
+func (r *reflector) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: PrimitiveTypeList{ObjectType}}
+	properties := map[string]*Schema{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			// Promote the fields of an embedded struct (or pointer to struct) type.
+			ft := derefType(f.Type)
+			if ft.Kind() == reflect.Struct && !isSpecialType(ft) {
+				schema.AllOf = append(schema.AllOf, r.ref(ft))
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+		} else if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := r.schema(f.Type)
+		if hasTagOption(opts, "string") && isQuotedKind(f.Type) {
+			prop = &Schema{Type: PrimitiveTypeList{StringType}} // encoding/json encodes the value in a string
+		}
+		if prop.IsNegated {
+			continue // encoding/json can't encode the field
+		}
+		required := f.Type.Kind() != reflect.Pointer && !hasTagOption(opts, "omitempty")
+		if tag, ok := f.Tag.Lookup("jsonschema"); ok {
+			var err error
+			if prop, required, err = applySchemaTag(prop, required, derefType(f.Type), tag); err != nil {
+				panic(fmt.Sprintf("jsonschema: invalid jsonschema struct tag on field %s of %s: %s", f.Name, t, err))
+			}
+		}
+		if isNilable(f.Type.Kind()) && !hasTagOption(opts, "omitempty") {
+			prop = nullableSchema(prop) // encoding/json encodes a nil value as null
+		}
+		properties[name] = prop
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	if len(properties) > 0 || len(schema.AllOf) == 0 {
+		schema.Properties = &properties
+	}
+	return schema
+}
+
+// nullableSchema returns a schema that allows null and the values that schema allows.
+func nullableSchema(schema *Schema) *Schema {
+	switch {
+	case schema.IsEmpty:
+		return schema
+	case len(schema.Type) == 0:
+		return &Schema{AnyOf: []*Schema{schema, {Type: PrimitiveTypeList{NullType}}}}
+	}
+	schema.Type = append(schema.Type, NullType)
+	if schema.Enum != nil {
+		schema.Enum = append(schema.Enum, nil)
+	}
+	return schema
+}
+
+// applySchemaTag sets the keywords given by the "jsonschema" struct tag (of a field of the Go type
+// t) in schema. It returns the schema and whether the property is required.
+func applySchemaTag(schema *Schema, required bool, t reflect.Type, tag string) (*Schema, bool, error) {
+	wrapped := false
+	for _, pair := range splitSchemaTag(tag) {
+		key, value, hasValue := strings.Cut(pair, "=")
+		if key == "required" && !hasValue {
+			required = true
+			continue
+		}
+		if !hasValue {
+			return nil, false, fmt.Errorf("missing value for key %q", key)
+		}
+		if (schema.Reference != nil || schema.IsEmpty) && !wrapped {
+			// Keywords can't be added to a $ref (in draft-07) or to the "true" schema.
+			schema, wrapped = &Schema{AllOf: []*Schema{schema}}, true
+		}
+
+		var err error
+		switch key {
+		case "title":
+			schema.Title = &value
+		case "description":
+			schema.Description = &value
+		case "format":
+			format := Format(value)
+			schema.Format = &format
+		case "pattern":
+			schema.Pattern = &value
+		case "enum":
+			for _, s := range strings.Split(value, "|") {
+				v, err := parseSchemaTagValue(t, s)
+				if err != nil {
+					return nil, false, fmt.Errorf("enum: %w", err)
+				}
+				schema.Enum = append(schema.Enum, v)
+			}
+		case "default":
+			var v any
+			if v, err = parseSchemaTagValue(t, value); err == nil {
+				schema.Default = &v
+			}
+		case "minimum":
+			schema.Minimum, err = parseFloatPtr(value)
+		case "maximum":
+			schema.Maximum, err = parseFloatPtr(value)
+		case "exclusiveMinimum":
+			schema.ExclusiveMinimum, err = parseFloatPtr(value)
+		case "exclusiveMaximum":
+			schema.ExclusiveMaximum, err = parseFloatPtr(value)
+		case "multipleOf":
+			schema.MultipleOf, err = parseFloatPtr(value)
+		case "minLength":
+			schema.MinLength, err = parseIntPtr(value)
+		case "maxLength":
+			schema.MaxLength, err = parseIntPtr(value)
+		case "minItems":
+			schema.MinItems, err = parseIntPtr(value)
+		case "maxItems":
+			schema.MaxItems, err = parseIntPtr(value)
+		case "minProperties":
+			schema.MinProperties, err = parseIntPtr(value)
+		case "maxProperties":
+			schema.MaxProperties, err = parseIntPtr(value)
+		case "uniqueItems":
+			var b bool
+			if b, err = strconv.ParseBool(value); err == nil {
+				schema.UniqueItems = &b
+			}
+		default:
+			return nil, false, fmt.Errorf("unknown key %q", key)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return schema, required, nil
+}
+
+// splitSchemaTag splits the "jsonschema" struct tag at commas (except for those escaped as "\,").
+func splitSchemaTag(tag string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			part.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(tag[i])
+		}
+	}
+	if part.Len() > 0 {
+		parts = append(parts, part.String())
+	}
+	return parts
+}
+
+// parseSchemaTagValue parses s (from a "jsonschema" struct tag) as a JSON value of the Go type t.
+// Strings are taken literally, and values of other types are parsed as JSON.
+func parseSchemaTagValue(t reflect.Type, s string) (any, error) {
+	if t.Kind() == reflect.String {
+		return s, nil
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("invalid JSON value %q", s)
+	}
+	return v, nil
+}
+
+func parseFloatPtr(s string) (*float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseIntPtr(s string) (*int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// integerSchema returns the integer schema for values of the Go integer type of the given kind.
+// The !go.integerType extension is set to the Go type (unless it is int), so that the Go type for
+// the schema is the same type.
+func integerSchema(kind reflect.Kind, unsigned bool) *Schema {
+	schema := &Schema{Type: PrimitiveTypeList{IntegerType}}
+	if unsigned {
+		zero := 0.0
+		schema.Minimum = &zero
+	}
+	if kind == reflect.Uintptr {
+		kind = reflect.Uint64
+	}
+	if kind != reflect.Int {
+		schema.Go = &GoExtensions{IntegerType: kind.String()}
+	}
+	return schema
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// implements reports whether values of type t (or pointers to them) implement the interface type.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// isSpecialType reports whether the Go struct type t has a JSON encoding other than an object (so
+// it is not represented by an object schema).
+func isSpecialType(t reflect.Type) bool {
+	return t == timeType || implements(t, jsonMarshalerType) || implements(t, textMarshalerType)
+}
+
+// derefType returns the type that the (possibly multiple levels of) pointer type t points to, or t
+// itself if it is not a pointer type.
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isQuotedKind reports whether the "string" option of the json struct tag applies to a field of
+// type t, which makes encoding/json encode a number or boolean value in a JSON string.
+func isQuotedKind(t reflect.Type) bool {
+	if t.Name() == "" && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if isSpecialType(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isNilable(kind reflect.Kind) bool {
+	return kind == reflect.Pointer || kind == reflect.Slice || kind == reflect.Map
+}
+
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-jsonschema/internal/testutil"
+)
+
+type reflectConfig struct {
+	reflectBase
+	Name     string            `json:"name" jsonschema:"description=The name\\, in full,minLength=1"`
+	Port     uint16            `json:"port,omitempty" jsonschema:"minimum=1,maximum=65535,default=8080"`
+	Mode     string            `json:"mode,omitempty" jsonschema:"enum=dev|prod"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Child    *reflectChild     `json:"child,omitempty"`
+	Children []*reflectChild   `json:"children"`
+	Created  time.Time         `json:"created"`
+	Extra    any               `json:"extra,omitempty"`
+	Ignored  string            `json:"-"`
+	Func     func()            `json:"func,omitempty"`
+	Weight   float64           `json:"weight,string"`
+	Enabled  *bool             `json:"enabled,omitempty,string"`
+	internal string
+}
+
+type reflectBase struct {
+	ID int64 `json:"id"`
+}
+
+type reflectChild struct {
+	Parent *reflectChild `json:"parent,omitempty" jsonschema:"required"`
+	Score  float64       `json:"score"`
+}
+
+func TestReflect(t *testing.T) {
+	got, err := json.Marshal(Reflect(reflectConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "reflectConfig",
+  "type": "object",
+  "allOf": [{"$ref": "#/definitions/reflectBase"}],
+  "required": ["name", "children", "created", "weight"],
+  "properties": {
+    "name": {"type": "string", "description": "The name, in full", "minLength": 1},
+    "port": {"type": "integer", "minimum": 1, "maximum": 65535, "default": 8080, "!go": {"integerType": "uint16"}},
+    "mode": {"type": "string", "enum": ["dev", "prod"]},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "child": {"$ref": "#/definitions/reflectChild"},
+    "children": {"type": ["array", "null"], "items": {"$ref": "#/definitions/reflectChild"}},
+    "created": {"type": "string", "format": "date-time"},
+    "extra": true,
+    "weight": {"type": "string"},
+    "enabled": {"type": "string"}
+  },
+  "definitions": {
+    "reflectBase": {
+      "type": "object",
+      "required": ["id"],
+      "properties": {
+        "id": {"type": "integer", "!go": {"integerType": "int64"}}
+      }
+    },
+    "reflectChild": {
+      "type": "object",
+      "required": ["parent", "score"],
+      "properties": {
+        "parent": {"$ref": "#/definitions/reflectChild"},
+        "score": {"type": "number"}
+      }
+    }
+  }
+}`)
+	if got, want := testutil.CanonicalJSON(got), testutil.CanonicalJSON(want); !bytes.Equal(got, want) {
+		t.Errorf("got %s\n\nwant %s", got, want)
+	}
+}
+
+func TestReflect_zeroValue(t *testing.T) {
+	// The fields without omitempty are encoded as null when they are nil.
+	type zero struct {
+		Child  *reflectChild     `json:"child"`
+		Labels map[string]string `json:"labels"`
+		Port   *uint16           `json:"port" jsonschema:"minimum=1"`
+		Mode   *string           `json:"mode" jsonschema:"enum=dev|prod"`
+		Tags   []string          `json:"tags" jsonschema:"minItems=1"`
+		Data   []byte            `json:"data"`
+	}
+	schema := Reflect(zero{})
+	data, err := json.Marshal(zero{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(schema, data); err != nil {
+		t.Errorf("got error %v for %s, want valid", err, data)
+	}
+
+	// Non-null values are still validated.
+	data, err = json.Marshal(zero{Port: new(uint16)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(schema, data); err == nil {
+		t.Errorf("got valid for %s, want error", data)
+	}
+}
+
+func TestReflect_nonStruct(t *testing.T) {
+	got, err := json.Marshal(Reflect([]reflectBase{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "array",
+  "items": {"$ref": "#/definitions/reflectBase"},
+  "definitions": {
+    "reflectBase": {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer", "!go": {"integerType": "int64"}}}}
+  }
+}`)
+	if got, want := testutil.CanonicalJSON(got), testutil.CanonicalJSON(want); !bytes.Equal(got, want) {
+		t.Errorf("got %s\n\nwant %s", got, want)
+	}
+}
+
+func TestReflect_invalidTag(t *testing.T) {
+	type invalid struct {
+		A int `json:"a" jsonschema:"minimum=x"`
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("want panic for invalid jsonschema struct tag")
+		}
+	}()
+	Reflect(invalid{})
+}
@@ -6,66 +6,99 @@ import (
 	"fmt"
 )
 
-// Schema is a JSON Schema draft-07 document (as specified in
-// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01)).
+// Schema is a JSON Schema document (as specified in
+// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01) for
+// draft-07, and in the [2019-09](https://json-schema.org/specification-links#draft-2019-09) and
+// [2020-12](https://json-schema.org/specification-links#2020-12) drafts).
+//
+// It has fields for the keywords of draft-07 and for the commonly used keywords that were added in
+// 2019-09 and 2020-12 (such as "$defs", "prefixItems", and "unevaluatedProperties"). Use Dialect to
+// determine which draft a schema is written for.
 type Schema struct {
-	Comment              *string                      `json:"$comment,omitempty"`
-	ID                   *string                      `json:"$id,omitempty"`
-	Reference            *string                      `json:"$ref,omitempty"`
-	SchemaRef            *string                      `json:"$schema,omitempty"`
-	AdditionalItems      *Schema                      `json:"additionalItems,omitempty"`
-	AdditionalProperties *Schema                      `json:"additionalProperties,omitempty"`
-	AllOf                []*Schema                    `json:"allOf,omitempty"`
-	AnyOf                []*Schema                    `json:"anyOf,omitempty"`
-	Const                *any                         `json:"const,omitempty"`
-	Contains             *Schema                      `json:"contains,omitempty"`
-	Default              *any                         `json:"default,omitempty"`
-	Definitions          *map[string]*Schema          `json:"definitions,omitempty"`
-	Dependencies         *map[string]*DependencyValue `json:"dependencies,omitempty"`
-	Description          *string                      `json:"description,omitempty"`
-	Else                 *Schema                      `json:"else,omitempty"`
-	Enum                 EnumList                     `json:"enum,omitempty"`
-	Examples             []any                        `json:"examples,omitempty"`
-	ExclusiveMaximum     *float64                     `json:"exclusiveMaximum,omitempty"`
-	ExclusiveMinimum     *float64                     `json:"exclusiveMinimum,omitempty"`
-	Format               *Format                      `json:"format,omitempty"`
-	If                   *Schema                      `json:"if,omitempty"`
-	Items                *SchemaOrSchemaList          `json:"items,omitempty"`
-	MaxItems             *int64                       `json:"maxItems,omitempty"`
-	MaxLength            *int64                       `json:"maxLength,omitempty"`
-	MaxProperties        *int64                       `json:"maxProperties,omitempty"`
-	Maximum              *float64                     `json:"maximum,omitempty"`
-	MinItems             *int64                       `json:"minItems,omitempty"`
-	MinLength            *int64                       `json:"minLength,omitempty"`
-	MinProperties        *int64                       `json:"minProperties,omitempty"`
-	Minimum              *float64                     `json:"minimum,omitempty"`
-	MultipleOf           *float64                     `json:"multipleOf,omitempty"`
-	Not                  *Schema                      `json:"not,omitempty"`
-	OneOf                []*Schema                    `json:"oneOf,omitempty"`
-	Pattern              *string                      `json:"pattern,omitempty"`
-	PatternProperties    *map[string]*Schema          `json:"patternProperties,omitempty"`
-	Properties           *map[string]*Schema          `json:"properties,omitempty"`
-	PropertyNames        *Schema                      `json:"propertyNames,omitempty"`
-	Required             []string                     `json:"required,omitempty"`
-	Then                 *Schema                      `json:"then,omitempty"`
-	Title                *string                      `json:"title,omitempty"`
-	Type                 PrimitiveTypeList            `json:"type,omitempty"`
-	UniqueItems          *bool                        `json:"uniqueItems,omitempty"`
+	Anchor                *string                      `json:"$anchor,omitempty"`
+	Comment               *string                      `json:"$comment,omitempty"`
+	Defs                  *map[string]*Schema          `json:"$defs,omitempty"`
+	DynamicAnchor         *string                      `json:"$dynamicAnchor,omitempty"`
+	DynamicRef            *string                      `json:"$dynamicRef,omitempty"`
+	ID                    *string                      `json:"$id,omitempty"`
+	Reference             *string                      `json:"$ref,omitempty"`
+	SchemaRef             *string                      `json:"$schema,omitempty"`
+	AdditionalItems       *Schema                      `json:"additionalItems,omitempty"`
+	AdditionalProperties  *Schema                      `json:"additionalProperties,omitempty"`
+	AllOf                 []*Schema                    `json:"allOf,omitempty"`
+	AnyOf                 []*Schema                    `json:"anyOf,omitempty"`
+	Const                 *any                         `json:"const,omitempty"`
+	Contains              *Schema                      `json:"contains,omitempty"`
+	Default               *any                         `json:"default,omitempty"`
+	Definitions           *map[string]*Schema          `json:"definitions,omitempty"`
+	Dependencies          *map[string]*DependencyValue `json:"dependencies,omitempty"`
+	DependentRequired     *map[string][]string         `json:"dependentRequired,omitempty"`
+	DependentSchemas      *map[string]*Schema          `json:"dependentSchemas,omitempty"`
+	Description           *string                      `json:"description,omitempty"`
+	Else                  *Schema                      `json:"else,omitempty"`
+	Enum                  EnumList                     `json:"enum,omitempty"`
+	Examples              []any                        `json:"examples,omitempty"`
+	ExclusiveMaximum      *float64                     `json:"exclusiveMaximum,omitempty"`
+	ExclusiveMinimum      *float64                     `json:"exclusiveMinimum,omitempty"`
+	Format                *Format                      `json:"format,omitempty"`
+	If                    *Schema                      `json:"if,omitempty"`
+	Items                 *SchemaOrSchemaList          `json:"items,omitempty"`
+	MaxContains           *int64                       `json:"maxContains,omitempty"`
+	MaxItems              *int64                       `json:"maxItems,omitempty"`
+	MaxLength             *int64                       `json:"maxLength,omitempty"`
+	MaxProperties         *int64                       `json:"maxProperties,omitempty"`
+	Maximum               *float64                     `json:"maximum,omitempty"`
+	MinContains           *int64                       `json:"minContains,omitempty"`
+	MinItems              *int64                       `json:"minItems,omitempty"`
+	MinLength             *int64                       `json:"minLength,omitempty"`
+	MinProperties         *int64                       `json:"minProperties,omitempty"`
+	Minimum               *float64                     `json:"minimum,omitempty"`
+	MultipleOf            *float64                     `json:"multipleOf,omitempty"`
+	Not                   *Schema                      `json:"not,omitempty"`
+	OneOf                 []*Schema                    `json:"oneOf,omitempty"`
+	Pattern               *string                      `json:"pattern,omitempty"`
+	PatternProperties     *map[string]*Schema          `json:"patternProperties,omitempty"`
+	PrefixItems           []*Schema                    `json:"prefixItems,omitempty"`
+	Properties            *map[string]*Schema          `json:"properties,omitempty"`
+	PropertyNames         *Schema                      `json:"propertyNames,omitempty"`
+	Required              []string                     `json:"required,omitempty"`
+	Then                  *Schema                      `json:"then,omitempty"`
+	Title                 *string                      `json:"title,omitempty"`
+	Type                  PrimitiveTypeList            `json:"type,omitempty"`
+	UnevaluatedItems      *Schema                      `json:"unevaluatedItems,omitempty"`
+	UnevaluatedProperties *Schema                      `json:"unevaluatedProperties,omitempty"`
+	UniqueItems           *bool                        `json:"uniqueItems,omitempty"`
 
 	// Raw is the raw JSON document that this schema was unmarshaled from, if any. It can be used to
 	// retrieve and set custom properties (such as for extensions to JSON Schema). It is omitted
 	// from the JSON encoding of this value.
 	Raw *json.RawMessage `json:"-"`
 
+	// RetrievalURI is the URI that this root schema document was loaded from (see Load), if any.
+	// It is the base URI against which a relative "$id" and (if there is no "$id") the $refs in the
+	// document are resolved. Unlike "$id", it is not part of the document, so it is omitted from
+	// the JSON encoding of this value.
+	RetrievalURI string `json:"-"`
+
 	IsEmpty   bool `json:"-"` // the schema is "true"
 	IsNegated bool `json:"-"` // the schema is "false"
 
 	// Go contains Go-specific extensions that JSON Schema authors can specify.
-	Go *struct {
-		TaggedUnionType bool   `json:"taggedUnionType,omitempty"`
-		Pointer         bool   `json:"pointer,omitempty"`
-		TypeName        string `json:"typeName,omitempty"`
-	} `json:"!go,omitempty"`
+	Go *GoExtensions `json:"!go,omitempty"`
+}
+
+// GoExtensions are the Go-specific extensions to JSON Schema (in the "!go" keyword).
+type GoExtensions struct {
+	TaggedUnionType bool   `json:"taggedUnionType,omitempty"`
+	Pointer         bool   `json:"pointer,omitempty"`
+	TypeName        string `json:"typeName,omitempty"`
+	IntegerType     string `json:"integerType,omitempty"`
+
+	// Import and Type bind the schema to an existing Go named type (Type in the package with the
+	// import path Import, or in the generated package if Import is empty), which is used instead of
+	// generating a Go type for the schema.
+	Import string `json:"import,omitempty"`
+	Type   string `json:"type,omitempty"`
 }
 
 // IsRequiredProperty reports whether propertyName is a required property for instances of this
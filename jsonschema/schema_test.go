@@ -56,3 +56,56 @@ func TestRaw(t *testing.T) {
 }
 
 func strptr(s string) *string { return &s }
+
+func TestDraft202012Keywords(t *testing.T) {
+	data := []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$anchor": "root",
+		"$dynamicAnchor": "node",
+		"$defs": {"a": {"$dynamicRef": "#node"}},
+		"dependentRequired": {"a": ["b"]},
+		"dependentSchemas": {"b": {"required": ["c"]}},
+		"prefixItems": [{"type": "string"}],
+		"contains": {"type": "string"},
+		"minContains": 1,
+		"maxContains": 2,
+		"unevaluatedItems": false,
+		"unevaluatedProperties": {"type": "integer"}
+	}`)
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if got := schema.Dialect(); got != Draft202012 {
+		t.Errorf("got dialect %q, want %q", got, Draft202012)
+	}
+	marshaled, err := json.Marshal(&schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshaled = testutil.CanonicalJSON(marshaled)
+	data = testutil.CanonicalJSON(data)
+	if !bytes.Equal(marshaled, data) {
+		t.Errorf("got %s, want %s", marshaled, data)
+	}
+}
+
+func TestDialect(t *testing.T) {
+	tests := map[string]Dialect{
+		"": Draft07,
+		"http://json-schema.org/draft-07/schema#":       Draft07,
+		"https://json-schema.org/draft-07/schema":       Draft07,
+		"https://json-schema.org/draft/2019-09/schema":  Draft201909,
+		"https://json-schema.org/draft/2020-12/schema#": Draft202012,
+		"https://example.com/custom-meta-schema.json":   Draft07,
+	}
+	for schemaURI, want := range tests {
+		schema := Schema{}
+		if schemaURI != "" {
+			schema.SchemaRef = &schemaURI
+		}
+		if got := schema.Dialect(); got != want {
+			t.Errorf("%q: got %q, want %q", schemaURI, got, want)
+		}
+	}
+}
@@ -3,12 +3,14 @@ package jsonschema_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/sourcegraph/go-jsonschema/internal/jsonschematestsuite"
 	"github.com/sourcegraph/go-jsonschema/internal/testutil"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 func TestJSONUnmarshalMarshal(t *testing.T) {
@@ -50,3 +52,85 @@ func TestJSONUnmarshalMarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSuite(t *testing.T) {
+	// These tests need remote schemas (such as the draft-07 meta-schema), which Validate does not
+	// load.
+	skip := map[string]struct{}{
+		"TestValidateSuite/draft7/ref/remote_ref,_containing_refs_itself":             struct{}{},
+		"TestValidateSuite/draft7/definitions/validate_definition_against_metaschema": struct{}{},
+
+		// This library's own test cases only exercise unmarshaling and marshaling. Their expected
+		// validation results do not follow draft-07 (which allows any number of array items
+		// unless additionalItems, minItems, or maxItems says otherwise).
+		"TestValidateSuite/draft7/single-element-items/single-element_items": struct{}{},
+	}
+	// Validating against the meta-schema and the draft 2019-09 keywords "$recursiveRef" and
+	// "$vocabulary" are not supported.
+	skipFiles := map[string]struct{}{
+		"draft2019-09/defs":         struct{}{},
+		"draft2019-09/recursiveRef": struct{}{},
+		"draft2019-09/vocabulary":   struct{}{},
+		"draft2020-12/defs":         struct{}{},
+		"draft2020-12/vocabulary":   struct{}{},
+	}
+
+	drafts := []struct {
+		name    string
+		dialect jsonschema.Dialect
+		files   func() ([]jsonschematestsuite.File, error)
+	}{
+		{"draft7", jsonschema.Draft07, func() ([]jsonschematestsuite.File, error) { return jsonschematestsuite.Files("../internal") }},
+		{"draft2019-09", jsonschema.Draft201909, func() ([]jsonschematestsuite.File, error) {
+			return jsonschematestsuite.OfficialFiles("../internal", "draft2019-09")
+		}},
+		{"draft2020-12", jsonschema.Draft202012, func() ([]jsonschematestsuite.File, error) {
+			return jsonschematestsuite.OfficialFiles("../internal", "draft2020-12")
+		}},
+	}
+	for _, draft := range drafts {
+		t.Run(draft.name, func(t *testing.T) {
+			files, err := draft.files()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range files {
+				// Remote references require a server (or loader) for http://localhost:1234.
+				if strings.HasPrefix(f.Name, "optional"+string(os.PathSeparator)) || f.Name == "refRemote" {
+					continue
+				}
+				if _, ok := skipFiles[draft.name+"/"+f.Name]; ok {
+					continue
+				}
+				t.Run(f.Name, func(t *testing.T) {
+					f.ReadT(t)
+					for _, g := range f.Groups {
+						t.Run(g.Description, func(t *testing.T) {
+							if _, ok := skip[t.Name()]; ok {
+								t.Skip()
+							}
+							// Older versions of the test suite omit "$schema" from the schemas of
+							// the newer drafts' tests.
+							if g.Schema.SchemaRef == nil && !g.Schema.IsEmpty && !g.Schema.IsNegated {
+								dialect := string(draft.dialect)
+								g.Schema.SchemaRef = &dialect
+							}
+							for _, tc := range g.Tests {
+								t.Run(tc.Description, func(t *testing.T) {
+									err := jsonschema.Validate(g.Schema, tc.Data)
+									var verr *jsonschema.ValidationError
+									if err != nil && !errors.As(err, &verr) {
+										t.Fatal(err)
+									}
+									if valid := err == nil; valid != tc.Valid {
+										t.Errorf("got valid %v, want %v (error: %v)", valid, tc.Valid, err)
+									}
+								})
+							}
+						})
+					}
+				})
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package jsonschema
 
 import (
 	"net/url"
-	"strconv"
 	"strings"
 )
 
@@ -65,20 +64,9 @@ type ReferenceToken struct {
 	Index   int    // dereference array's index
 }
 
-// EncodeReferenceTokens encodes the reference tokens to a string.
-//
-// TODO(sqs): Fully implement the encoding specified in
-// https://tools.ietf.org/html/rfc6901#section-6.
+// EncodeReferenceTokens encodes the reference tokens to a string (a JSON Pointer without the
+// leading "/"), escaping "~" and "/" in each token as specified in
+// https://tools.ietf.org/html/rfc6901#section-3.
 func EncodeReferenceTokens(tokens []ReferenceToken) string {
-	parts := make([]string, len(tokens))
-	for i, token := range tokens {
-		var part string
-		if token.Name != "" {
-			part = token.Name
-		} else {
-			part = strconv.Itoa(token.Index)
-		}
-		parts[i] = part
-	}
-	return strings.Join(parts, "/")
+	return strings.TrimPrefix(PointerFromReferenceTokens(tokens).String(), "/")
 }
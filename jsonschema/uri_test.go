@@ -23,3 +23,10 @@ func TestID_URI(t *testing.T) {
 		i++
 	}
 }
+
+func TestEncodeReferenceTokens(t *testing.T) {
+	tokens := []ReferenceToken{{Name: "definitions", Keyword: true}, {Name: "a/b~c"}, {Index: 1}}
+	if got, want := EncodeReferenceTokens(tokens), "definitions/a~1b~0c/1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
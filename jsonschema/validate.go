@@ -0,0 +1,732 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate reports whether the JSON document instance is valid against the JSON Schema, according
+// to the schema's dialect (see Schema.Dialect).
+//
+// It returns nil if the instance is valid and a *ValidationError describing the failed keywords if
+// it is not. Any other error (such as malformed JSON, or a $ref that can't be resolved or that leads
+// back to the same schema for the same instance) is returned as-is.
+//
+// The "format" keyword is treated as an annotation and is not validated.
+func Validate(schema *Schema, instance []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(instance))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON instance: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("failed to unmarshal JSON instance: unexpected data after top-level value")
+	}
+
+	v, err := newValidator(schema)
+	if err != nil {
+		return err
+	}
+	verr := v.validateSubschema(schema, value, nil, nil, "instance is invalid against the schema")
+	if v.err != nil {
+		return v.err
+	}
+	if verr != nil {
+		return verr
+	}
+	return nil
+}
+
+// A ValidationError describes why a JSON instance (or a value in it) is invalid against a JSON
+// Schema (or one of its keywords). It is the root of a tree: Causes holds the errors from the
+// subschemas or individual keywords that led to this error, if any.
+type ValidationError struct {
+	// InstanceLocation is the location of the invalid value in the JSON instance.
+	InstanceLocation []ReferenceToken
+
+	// KeywordLocation is the location of the keyword (or subschema) that rejected the value,
+	// following the path taken through the schema during validation (including through "$ref").
+	KeywordLocation []ReferenceToken
+
+	// AbsoluteKeywordLocation is the dereferenced location of the keyword (or subschema) that
+	// rejected the value, relative to the base URI of the schema document that contains it.
+	AbsoluteKeywordLocation ID
+
+	Message string
+	Causes  []*ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	var buf strings.Builder
+	buf.WriteString(e.Message)
+	if leaves := e.leaves(); len(leaves) > 1 || (len(leaves) == 1 && leaves[0] != e) {
+		buf.WriteString(": ")
+		for i, leaf := range leaves {
+			if i > 0 {
+				buf.WriteString("; ")
+			}
+			if len(leaf.InstanceLocation) > 0 {
+				buf.WriteString(PointerFromReferenceTokens(leaf.InstanceLocation).String())
+				buf.WriteString(": ")
+			}
+			buf.WriteString(leaf.Message)
+		}
+	}
+	return buf.String()
+}
+
+// leaves returns the errors in the tree rooted at e that have no causes.
+func (e *ValidationError) leaves() []*ValidationError {
+	if len(e.Causes) == 0 {
+		return []*ValidationError{e}
+	}
+	var leaves []*ValidationError
+	for _, c := range e.Causes {
+		leaves = append(leaves, c.leaves()...)
+	}
+	return leaves
+}
+
+// validator validates instances against a root schema and the subschemas it contains.
+type validator struct {
+	dialect Dialect
+	refs    *schemaIndex
+	regexps map[string]*regexp.Regexp
+
+	// scope is the base URIs of the schema resources entered during validation so far (the
+	// outermost first), for resolving "$dynamicRef".
+	scope []*url.URL
+
+	// activeRefs is the schemas that are being evaluated (after following a "$ref" or "$dynamicRef") and
+	// the instances they are evaluated against, for detecting reference cycles.
+	activeRefs map[activeRef]struct{}
+
+	// err is the first non-validation error encountered (such as an unresolvable $ref or invalid
+	// regular expression). Validation results are meaningless if it is set.
+	err error
+}
+
+func newValidator(root *Schema) (*validator, error) {
+	refs, err := indexSchema(root)
+	if err != nil {
+		return nil, err
+	}
+	return &validator{dialect: root.Dialect(), refs: refs, regexps: map[string]*regexp.Regexp{}, activeRefs: map[activeRef]struct{}{}}, nil
+}
+
+// activeRef is a schema that is being evaluated against the instance at a location.
+type activeRef struct {
+	schema   *Schema
+	instLoc  string
+	instance any // the instance itself if it is a JSON scalar, or the address of its Go map or slice
+}
+
+// enterRef records that target (the schema that a reference resolved to) is being evaluated
+// against instance (at instLoc), and returns a func that removes that record. It returns an error
+// if it already is, which means that the references form a cycle that would never end.
+func (v *validator) enterRef(target *Schema, instance any, instLoc []ReferenceToken) (func(), error) {
+	key := activeRef{schema: target, instLoc: PointerFromReferenceTokens(instLoc).String(), instance: instance}
+	switch instance.(type) {
+	case map[string]any, []any:
+		key.instance = reflect.ValueOf(instance).Pointer()
+	}
+	if _, ok := v.activeRefs[key]; ok {
+		return nil, fmt.Errorf("reference cycle at %s for the instance at %q", v.refs.locationOf[target], key.instLoc)
+	}
+	v.activeRefs[key] = struct{}{}
+	return func() { delete(v.activeRefs, key) }, nil
+}
+
+// validateSubschema validates instance (at instLoc) against schema (at kwLoc). If the instance is
+// invalid, it returns an error with the given message whose causes are the errors from schema's
+// keywords.
+func (v *validator) validateSubschema(schema *Schema, instance any, instLoc, kwLoc []ReferenceToken, message string) *ValidationError {
+	if base := v.refs.baseOf[schema]; base != nil && (len(v.scope) == 0 || v.scope[len(v.scope)-1] != base) {
+		v.scope = append(v.scope, base)
+		defer func() { v.scope = v.scope[:len(v.scope)-1] }()
+	}
+	causes := v.validate(schema, instance, instLoc, kwLoc)
+	if len(causes) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		InstanceLocation:        instLoc,
+		KeywordLocation:         kwLoc,
+		AbsoluteKeywordLocation: v.refs.locationOf[schema],
+		Message:                 message,
+		Causes:                  causes,
+	}
+}
+
+// keywordError returns an error for the keyword of schema (at kwLoc) that rejected the instance (at
+// instLoc).
+func (v *validator) keywordError(schema *Schema, keyword string, instLoc, kwLoc []ReferenceToken, causes []*ValidationError, format string, args ...any) *ValidationError {
+	rel := []ReferenceToken{{Name: keyword, Keyword: true}}
+	return &ValidationError{
+		InstanceLocation:        instLoc,
+		KeywordLocation:         appendReferenceTokens(kwLoc, rel),
+		AbsoluteKeywordLocation: v.refs.locationOf[schema].ResolveReference(rel),
+		Message:                 fmt.Sprintf(format, args...),
+		Causes:                  causes,
+	}
+}
+
+// validate returns the validation errors for instance (at instLoc) against schema (at kwLoc) and
+// its subschemas.
+func (v *validator) validate(schema *Schema, instance any, instLoc, kwLoc []ReferenceToken) (errs []*ValidationError) {
+	if v.err != nil {
+		return nil
+	}
+	switch {
+	case schema.IsEmpty:
+		return nil
+	case schema.IsNegated:
+		return []*ValidationError{{
+			InstanceLocation:        instLoc,
+			KeywordLocation:         kwLoc,
+			AbsoluteKeywordLocation: v.refs.locationOf[schema],
+			Message:                 "no value is allowed by the false schema",
+		}}
+	}
+
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, v.keywordError(schema, keyword, instLoc, kwLoc, nil, format, args...))
+	}
+	sub := func(s *Schema, kwRel []ReferenceToken, format string, args ...any) bool {
+		err := v.validateSubschema(s, instance, instLoc, appendReferenceTokens(kwLoc, kwRel), fmt.Sprintf(format, args...))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return err == nil
+	}
+
+	if schema.Reference != nil {
+		target, err := v.refs.resolve(schema)
+		if err != nil {
+			v.err = err
+			return nil
+		}
+		leave, err := v.enterRef(target, instance, instLoc)
+		if err != nil {
+			v.err = err
+			return nil
+		}
+		sub(target, []ReferenceToken{{Name: "$ref", Keyword: true}}, "instance is invalid against $ref %q", *schema.Reference)
+		leave()
+
+		// In draft-07, all other keywords are ignored when $ref is present
+		// (https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.3).
+		if v.dialect.refOverridesSiblings() {
+			return errs
+		}
+	}
+	if schema.DynamicRef != nil {
+		target, err := v.resolveDynamicRef(schema)
+		if err != nil {
+			v.err = err
+			return nil
+		}
+		leave, err := v.enterRef(target, instance, instLoc)
+		if err != nil {
+			v.err = err
+			return nil
+		}
+		sub(target, []ReferenceToken{{Name: "$dynamicRef", Keyword: true}}, "instance is invalid against $dynamicRef %q", *schema.DynamicRef)
+		leave()
+	}
+
+	// Keywords for any instance type.
+	if len(schema.Type) > 0 && !hasType(schema.Type, instance) {
+		fail("type", "expected type %s, got %s", typeListString(schema.Type), instanceType(instance))
+	}
+	if schema.Enum != nil {
+		var ok bool
+		for _, e := range schema.Enum {
+			if jsonEqual(instance, e) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			fail("enum", "value must be one of the enum values")
+		}
+	}
+	if constValue, ok := schemaConst(schema); ok && !jsonEqual(instance, constValue) {
+		fail("const", "value must be equal to the const value")
+	}
+
+	switch instance := instance.(type) {
+	case json.Number, float64:
+		errs = append(errs, v.validateNumber(schema, toRat(instance), instLoc, kwLoc)...)
+	case string:
+		errs = append(errs, v.validateString(schema, instance, instLoc, kwLoc)...)
+	case []any:
+		errs = append(errs, v.validateArray(schema, instance, instLoc, kwLoc)...)
+	case map[string]any:
+		errs = append(errs, v.validateObject(schema, instance, instLoc, kwLoc)...)
+	}
+
+	// Keywords for applying subschemas conditionally.
+	if schema.If != nil {
+		if v.validateSubschema(schema.If, instance, instLoc, nil, "") == nil {
+			if schema.Then != nil {
+				sub(schema.Then, []ReferenceToken{{Name: "then", Keyword: true}}, `instance is invalid against the "then" schema`)
+			}
+		} else if schema.Else != nil {
+			sub(schema.Else, []ReferenceToken{{Name: "else", Keyword: true}}, `instance is invalid against the "else" schema`)
+		}
+	}
+
+	// Keywords for applying subschemas with boolean logic.
+	for i, s := range schema.AllOf {
+		sub(s, []ReferenceToken{{Name: "allOf", Keyword: true}, {Index: i}}, "instance is invalid against allOf schema %d", i)
+	}
+	if len(schema.AnyOf) > 0 {
+		var causes []*ValidationError
+		for i, s := range schema.AnyOf {
+			err := v.validateSubschema(s, instance, instLoc, appendReferenceTokens(kwLoc, []ReferenceToken{{Name: "anyOf", Keyword: true}, {Index: i}}), fmt.Sprintf("instance is invalid against anyOf schema %d", i))
+			if err == nil {
+				causes = nil
+				break
+			}
+			causes = append(causes, err)
+		}
+		if len(causes) > 0 {
+			errs = append(errs, v.keywordError(schema, "anyOf", instLoc, kwLoc, causes, "instance is invalid against all anyOf schemas"))
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		var causes []*ValidationError
+		var matched []int
+		for i, s := range schema.OneOf {
+			err := v.validateSubschema(s, instance, instLoc, appendReferenceTokens(kwLoc, []ReferenceToken{{Name: "oneOf", Keyword: true}, {Index: i}}), fmt.Sprintf("instance is invalid against oneOf schema %d", i))
+			if err == nil {
+				matched = append(matched, i)
+			} else {
+				causes = append(causes, err)
+			}
+		}
+		switch {
+		case len(matched) == 0:
+			errs = append(errs, v.keywordError(schema, "oneOf", instLoc, kwLoc, causes, "instance is invalid against all oneOf schemas"))
+		case len(matched) > 1:
+			fail("oneOf", "instance is valid against more than one oneOf schema (%v)", matched)
+		}
+	}
+	if schema.Not != nil && v.validateSubschema(schema.Not, instance, instLoc, nil, "") == nil {
+		fail("not", `instance must not be valid against the "not" schema`)
+	}
+
+	// Keywords that depend on the results of all of the above.
+	if schema.UnevaluatedProperties != nil || schema.UnevaluatedItems != nil {
+		errs = append(errs, v.validateUnevaluated(schema, instance, instLoc, kwLoc)...)
+	}
+
+	return errs
+}
+
+// resolveDynamicRef returns the schema that schema's $dynamicRef refers to.
+//
+// It initially resolves like $ref. If the resulting schema has a "$dynamicAnchor" that matches the
+// fragment of the $dynamicRef, then the outermost schema resource in the dynamic scope with the
+// same "$dynamicAnchor" is used instead.
+func (v *validator) resolveDynamicRef(schema *Schema) (*Schema, error) {
+	target, ref, err := v.refs.resolveReference(schema, "$dynamicRef", *schema.DynamicRef)
+	if err != nil {
+		return nil, err
+	}
+	if target.DynamicAnchor != nil && *target.DynamicAnchor == ref.Fragment {
+		for _, base := range v.scope {
+			u := *base
+			u.Fragment, u.RawFragment = ref.Fragment, ""
+			if s, ok := v.refs.dynamicAnchors[uriKey(&u)]; ok {
+				return s, nil
+			}
+		}
+	}
+	return target, nil
+}
+
+func (v *validator) validateNumber(schema *Schema, n *big.Rat, instLoc, kwLoc []ReferenceToken) (errs []*ValidationError) {
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, v.keywordError(schema, keyword, instLoc, kwLoc, nil, format, args...))
+	}
+	if schema.MultipleOf != nil {
+		if m := floatToRat(*schema.MultipleOf); m.Sign() != 0 && !new(big.Rat).Quo(n, m).IsInt() {
+			fail("multipleOf", "%s is not a multiple of %v", n.RatString(), *schema.MultipleOf)
+		}
+	}
+	if schema.Maximum != nil && n.Cmp(floatToRat(*schema.Maximum)) > 0 {
+		fail("maximum", "%s is greater than the maximum %v", n.RatString(), *schema.Maximum)
+	}
+	if schema.ExclusiveMaximum != nil && n.Cmp(floatToRat(*schema.ExclusiveMaximum)) >= 0 {
+		fail("exclusiveMaximum", "%s is not less than the exclusive maximum %v", n.RatString(), *schema.ExclusiveMaximum)
+	}
+	if schema.Minimum != nil && n.Cmp(floatToRat(*schema.Minimum)) < 0 {
+		fail("minimum", "%s is less than the minimum %v", n.RatString(), *schema.Minimum)
+	}
+	if schema.ExclusiveMinimum != nil && n.Cmp(floatToRat(*schema.ExclusiveMinimum)) <= 0 {
+		fail("exclusiveMinimum", "%s is not greater than the exclusive minimum %v", n.RatString(), *schema.ExclusiveMinimum)
+	}
+	return errs
+}
+
+func (v *validator) validateString(schema *Schema, s string, instLoc, kwLoc []ReferenceToken) (errs []*ValidationError) {
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, v.keywordError(schema, keyword, instLoc, kwLoc, nil, format, args...))
+	}
+	if schema.MaxLength != nil || schema.MinLength != nil {
+		n := int64(utf8.RuneCountInString(s))
+		if schema.MaxLength != nil && n > *schema.MaxLength {
+			fail("maxLength", "string length %d is greater than the maxLength %d", n, *schema.MaxLength)
+		}
+		if schema.MinLength != nil && n < *schema.MinLength {
+			fail("minLength", "string length %d is less than the minLength %d", n, *schema.MinLength)
+		}
+	}
+	if schema.Pattern != nil {
+		if re := v.regexp(*schema.Pattern); re != nil && !re.MatchString(s) {
+			fail("pattern", "string %q does not match the pattern %q", s, *schema.Pattern)
+		}
+	}
+	return errs
+}
+
+func (v *validator) validateArray(schema *Schema, items []any, instLoc, kwLoc []ReferenceToken) (errs []*ValidationError) {
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, v.keywordError(schema, keyword, instLoc, kwLoc, nil, format, args...))
+	}
+	sub := func(s *Schema, i int, kwRel []ReferenceToken, format string, args ...any) bool {
+		err := v.validateSubschema(s, items[i], appendReferenceTokens(instLoc, []ReferenceToken{{Index: i}}), appendReferenceTokens(kwLoc, kwRel), fmt.Sprintf(format, args...))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return err == nil
+	}
+
+	for i, s := range schema.PrefixItems {
+		if i < len(items) {
+			sub(s, i, []ReferenceToken{{Name: "prefixItems", Keyword: true}, {Index: i}}, "array item %d is invalid", i)
+		}
+	}
+	if schema.Items != nil {
+		if schema.Items.Schema != nil {
+			// The "items" schema applies to the items after those matched by "prefixItems" (if
+			// any).
+			for i := len(schema.PrefixItems); i < len(items); i++ {
+				sub(schema.Items.Schema, i, []ReferenceToken{{Name: "items", Keyword: true}}, "array item %d is invalid", i)
+			}
+		} else {
+			for i := range items {
+				if i < len(schema.Items.Schemas) {
+					sub(schema.Items.Schemas[i], i, []ReferenceToken{{Name: "items", Keyword: true}, {Index: i}}, "array item %d is invalid", i)
+				} else if schema.AdditionalItems != nil {
+					sub(schema.AdditionalItems, i, []ReferenceToken{{Name: "additionalItems", Keyword: true}}, "additional array item %d is invalid", i)
+				}
+			}
+		}
+	}
+	if schema.MaxItems != nil && int64(len(items)) > *schema.MaxItems {
+		fail("maxItems", "array length %d is greater than the maxItems %d", len(items), *schema.MaxItems)
+	}
+	if schema.MinItems != nil && int64(len(items)) < *schema.MinItems {
+		fail("minItems", "array length %d is less than the minItems %d", len(items), *schema.MinItems)
+	}
+	if schema.UniqueItems != nil && *schema.UniqueItems {
+	outer:
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if jsonEqual(items[i], items[j]) {
+					fail("uniqueItems", "array items %d and %d are equal but uniqueItems is true", i, j)
+					break outer
+				}
+			}
+		}
+	}
+	if schema.Contains != nil {
+		var n int64
+		for _, item := range items {
+			if v.validateSubschema(schema.Contains, item, nil, nil, "") == nil {
+				n++
+			}
+		}
+		switch {
+		case schema.MinContains != nil && n < *schema.MinContains:
+			fail("minContains", `%d array items are valid against the "contains" schema, fewer than the minContains %d`, n, *schema.MinContains)
+		case schema.MinContains == nil && n == 0:
+			fail("contains", `no array item is valid against the "contains" schema`)
+		}
+		if schema.MaxContains != nil && n > *schema.MaxContains {
+			fail("maxContains", `%d array items are valid against the "contains" schema, more than the maxContains %d`, n, *schema.MaxContains)
+		}
+	}
+	return errs
+}
+
+func (v *validator) validateObject(schema *Schema, object map[string]any, instLoc, kwLoc []ReferenceToken) (errs []*ValidationError) {
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, v.keywordError(schema, keyword, instLoc, kwLoc, nil, format, args...))
+	}
+	sub := func(s *Schema, instance any, instRel, kwRel []ReferenceToken, format string, args ...any) bool {
+		err := v.validateSubschema(s, instance, appendReferenceTokens(instLoc, instRel), appendReferenceTokens(kwLoc, kwRel), fmt.Sprintf(format, args...))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return err == nil
+	}
+
+	// Iterate over properties in a deterministic order so that errors are reported consistently.
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if schema.MaxProperties != nil && int64(len(object)) > *schema.MaxProperties {
+		fail("maxProperties", "object has %d properties, more than the maxProperties %d", len(object), *schema.MaxProperties)
+	}
+	if schema.MinProperties != nil && int64(len(object)) < *schema.MinProperties {
+		fail("minProperties", "object has %d properties, fewer than the minProperties %d", len(object), *schema.MinProperties)
+	}
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			fail("required", "missing required property %q", name)
+		}
+	}
+
+	for _, name := range names {
+		value := object[name]
+		instRel := []ReferenceToken{{Name: name}}
+		var matched bool
+		if schema.Properties != nil {
+			if s, ok := (*schema.Properties)[name]; ok {
+				matched = true
+				sub(s, value, instRel, []ReferenceToken{{Name: "properties", Keyword: true}, {Name: name}}, "property %q is invalid", name)
+			}
+		}
+		if schema.PatternProperties != nil {
+			patterns := make([]string, 0, len(*schema.PatternProperties))
+			for pattern := range *schema.PatternProperties {
+				patterns = append(patterns, pattern)
+			}
+			sort.Strings(patterns)
+			for _, pattern := range patterns {
+				if re := v.regexp(pattern); re != nil && re.MatchString(name) {
+					matched = true
+					sub((*schema.PatternProperties)[pattern], value, instRel, []ReferenceToken{{Name: "patternProperties", Keyword: true}, {Name: pattern}}, "property %q is invalid against patternProperties %q", name, pattern)
+				}
+			}
+		}
+		if !matched && schema.AdditionalProperties != nil {
+			sub(schema.AdditionalProperties, value, instRel, []ReferenceToken{{Name: "additionalProperties", Keyword: true}}, "additional property %q is invalid", name)
+		}
+		if schema.PropertyNames != nil {
+			sub(schema.PropertyNames, name, instRel, []ReferenceToken{{Name: "propertyNames", Keyword: true}}, "property name %q is invalid", name)
+		}
+	}
+
+	if schema.Dependencies != nil {
+		deps := make([]string, 0, len(*schema.Dependencies))
+		for name := range *schema.Dependencies {
+			deps = append(deps, name)
+		}
+		sort.Strings(deps)
+		for _, name := range deps {
+			dep := (*schema.Dependencies)[name]
+			if _, ok := object[name]; !ok || dep == nil {
+				continue
+			}
+			if dep.Schema != nil {
+				sub(dep.Schema, object, nil, []ReferenceToken{{Name: "dependencies", Keyword: true}, {Name: name}}, "instance is invalid against the dependency schema for property %q", name)
+			}
+			for _, req := range dep.RequiredProperties {
+				if _, ok := object[req]; !ok {
+					fail("dependencies", "property %q is required by property %q", req, name)
+				}
+			}
+		}
+	}
+	if schema.DependentRequired != nil {
+		for _, name := range sortedKeys(*schema.DependentRequired) {
+			if _, ok := object[name]; !ok {
+				continue
+			}
+			for _, req := range (*schema.DependentRequired)[name] {
+				if _, ok := object[req]; !ok {
+					fail("dependentRequired", "property %q is required by property %q", req, name)
+				}
+			}
+		}
+	}
+	if schema.DependentSchemas != nil {
+		for _, name := range sortedKeys(*schema.DependentSchemas) {
+			if _, ok := object[name]; ok {
+				sub((*schema.DependentSchemas)[name], object, nil, []ReferenceToken{{Name: "dependentSchemas", Keyword: true}, {Name: name}}, "instance is invalid against the dependent schema for property %q", name)
+			}
+		}
+	}
+	return errs
+}
+
+func (v *validator) regexp(pattern string) *regexp.Regexp {
+	if re, ok := v.regexps[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		if v.err == nil {
+			v.err = fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		return nil
+	}
+	v.regexps[pattern] = re
+	return re
+}
+
+// schemaConst returns the schema's "const" value and whether it is set. It consults the raw JSON
+// for the schema because Schema.Const can't distinguish between an omitted const and "const":
+// null.
+func schemaConst(schema *Schema) (any, bool) {
+	if schema.Const != nil {
+		return *schema.Const, true
+	}
+	if schema.Raw == nil {
+		return nil, false
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(*schema.Raw, &m); err != nil {
+		return nil, false
+	}
+	if raw, ok := m["const"]; ok && bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, true
+	}
+	return nil, false
+}
+
+// instanceType returns the JSON Schema primitive type of the decoded JSON value. Numbers are
+// reported as NumberType (not IntegerType).
+func instanceType(instance any) PrimitiveType {
+	switch instance.(type) {
+	case nil:
+		return NullType
+	case bool:
+		return BooleanType
+	case json.Number, float64:
+		return NumberType
+	case string:
+		return StringType
+	case []any:
+		return ArrayType
+	case map[string]any:
+		return ObjectType
+	default:
+		panic(fmt.Sprintf("unexpected JSON value of type %T", instance))
+	}
+}
+
+func hasType(types PrimitiveTypeList, instance any) bool {
+	it := instanceType(instance)
+	for _, t := range types {
+		if t == it || (t == IntegerType && it == NumberType && toRat(instance).IsInt()) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeListString(types PrimitiveTypeList) string {
+	if len(types) == 1 {
+		return string(types[0])
+	}
+	s := make([]string, len(types))
+	for i, t := range types {
+		s[i] = string(t)
+	}
+	return "one of " + strings.Join(s, ", ")
+}
+
+// jsonEqual reports whether a and b (decoded JSON values) are equal according to JSON Schema. In
+// particular, numbers are compared by their mathematical value (so that 1 and 1.0 are equal).
+func jsonEqual(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number, float64:
+		switch b.(type) {
+		case json.Number, float64:
+			return toRat(a).Cmp(toRat(b)) == 0
+		}
+		return false
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !jsonEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// toRat returns the exact value of a JSON number decoded as a json.Number or float64.
+func toRat(n any) *big.Rat {
+	switch n := n.(type) {
+	case json.Number:
+		if r, ok := new(big.Rat).SetString(string(n)); ok {
+			return r
+		}
+		f, _ := n.Float64()
+		return floatToRat(f)
+	case float64:
+		return floatToRat(n)
+	default:
+		panic(fmt.Sprintf("unexpected JSON number of type %T", n))
+	}
+}
+
+// floatToRat returns the value of the shortest decimal representation of f, so that (for example)
+// 0.1 is represented exactly and not as the nearest binary floating-point value.
+func floatToRat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// sortedKeys returns the keys of m in sorted order, so that errors are reported consistently.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// OutputFormat is a standard format for reporting validation results (see [JSON Schema
+// 2019-09 section 10](https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.10)).
+type OutputFormat int
+
+const (
+	// FlagOutput reports only whether the instance is valid.
+	FlagOutput OutputFormat = iota
+
+	// BasicOutput reports a flat list of all errors.
+	BasicOutput
+
+	// DetailedOutput reports a tree of errors that mirrors the structure of the schema, omitting
+	// intermediate nodes that have only a single cause.
+	DetailedOutput
+)
+
+// OutputUnit is a single result in the basic or detailed output format.
+type OutputUnit struct {
+	Valid                   bool         `json:"valid"`
+	KeywordLocation         string       `json:"keywordLocation"`
+	AbsoluteKeywordLocation string       `json:"absoluteKeywordLocation,omitempty"`
+	InstanceLocation        string       `json:"instanceLocation"`
+	Error                   string       `json:"error,omitempty"`
+	Errors                  []OutputUnit `json:"errors,omitempty"`
+}
+
+// MarshalOutput returns the JSON encoding of the validation result in the given format (or an error
+// if the format is not one of the OutputFormat constants). A nil e means that the instance is
+// valid, so callers can pass the result of errors.As directly:
+//
+//	var verr *jsonschema.ValidationError
+//	errors.As(jsonschema.Validate(schema, instance), &verr)
+//	output, err := verr.MarshalOutput(jsonschema.BasicOutput)
+func (e *ValidationError) MarshalOutput(format OutputFormat) ([]byte, error) {
+	switch format {
+	case BasicOutput:
+		v := struct {
+			Valid  bool         `json:"valid"`
+			Errors []OutputUnit `json:"errors,omitempty"`
+		}{Valid: e == nil}
+		if e != nil {
+			e.walk(func(e *ValidationError) { v.Errors = append(v.Errors, e.outputUnit()) })
+		}
+		return json.Marshal(v)
+	case DetailedOutput:
+		if e == nil {
+			return json.Marshal(OutputUnit{Valid: true})
+		}
+		return json.Marshal(e.detailedOutputUnit(true))
+	case FlagOutput:
+		return json.Marshal(struct {
+			Valid bool `json:"valid"`
+		}{Valid: e == nil})
+	default:
+		return nil, fmt.Errorf("invalid output format %d", format)
+	}
+}
+
+// walk calls fn for each error in the tree rooted at e, in depth-first order.
+func (e *ValidationError) walk(fn func(*ValidationError)) {
+	fn(e)
+	for _, c := range e.Causes {
+		c.walk(fn)
+	}
+}
+
+func (e *ValidationError) outputUnit() OutputUnit {
+	return OutputUnit{
+		KeywordLocation:         PointerFromReferenceTokens(e.KeywordLocation).String(),
+		AbsoluteKeywordLocation: e.AbsoluteKeywordLocation.String(),
+		InstanceLocation:        PointerFromReferenceTokens(e.InstanceLocation).String(),
+		Error:                   e.Message,
+	}
+}
+
+func (e *ValidationError) detailedOutputUnit(root bool) OutputUnit {
+	if !root && len(e.Causes) == 1 {
+		return e.Causes[0].detailedOutputUnit(false)
+	}
+	u := e.outputUnit()
+	for _, c := range e.Causes {
+		u.Errors = append(u.Errors, c.detailedOutputUnit(false))
+	}
+	return u
+}
@@ -0,0 +1,81 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/internal/testutil"
+)
+
+func TestValidationError_MarshalOutput(t *testing.T) {
+	const schemaJSON = `{
+  "$id": "https://example.com/polygon",
+  "definitions": {
+    "point": {
+      "type": "object",
+      "properties": {"x": {"type": "number"}, "y": {"type": "number"}},
+      "additionalProperties": false,
+      "required": ["x", "y"]
+    }
+  },
+  "type": "array",
+  "items": {"$ref": "#/definitions/point"},
+  "minItems": 3
+}`
+	var schema Schema
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	var verr *ValidationError
+	if err := Validate(&schema, []byte(`[{"x":2.5,"y":1.3},{"x":1,"z":6.7}]`)); !errors.As(err, &verr) {
+		t.Fatalf("got error %v, want *ValidationError", err)
+	}
+
+	tests := map[OutputFormat]string{
+		FlagOutput: `{"valid":false}`,
+		BasicOutput: `{"valid":false,"errors":[
+  {"valid":false,"keywordLocation":"","absoluteKeywordLocation":"https://example.com/polygon","instanceLocation":"","error":"instance is invalid against the schema"},
+  {"valid":false,"keywordLocation":"/items","absoluteKeywordLocation":"https://example.com/polygon#/items","instanceLocation":"/1","error":"array item 1 is invalid"},
+  {"valid":false,"keywordLocation":"/items/$ref","absoluteKeywordLocation":"https://example.com/polygon#/definitions/point","instanceLocation":"/1","error":"instance is invalid against $ref \"#/definitions/point\""},
+  {"valid":false,"keywordLocation":"/items/$ref/required","absoluteKeywordLocation":"https://example.com/polygon#/definitions/point/required","instanceLocation":"/1","error":"missing required property \"y\""},
+  {"valid":false,"keywordLocation":"/items/$ref/additionalProperties","absoluteKeywordLocation":"https://example.com/polygon#/definitions/point/additionalProperties","instanceLocation":"/1/z","error":"additional property \"z\" is invalid"},
+  {"valid":false,"keywordLocation":"/items/$ref/additionalProperties","absoluteKeywordLocation":"https://example.com/polygon#/definitions/point/additionalProperties","instanceLocation":"/1/z","error":"no value is allowed by the false schema"},
+  {"valid":false,"keywordLocation":"/minItems","absoluteKeywordLocation":"https://example.com/polygon#/minItems","instanceLocation":"","error":"array length 2 is less than the minItems 3"}
+]}`,
+		DetailedOutput: `{"valid":false,"keywordLocation":"","absoluteKeywordLocation":"https://example.com/polygon","instanceLocation":"","error":"instance is invalid against the schema","errors":[
+  {"valid":false,"keywordLocation":"/items/$ref","absoluteKeywordLocation":"https://example.com/polygon#/definitions/point","instanceLocation":"/1","error":"instance is invalid against $ref \"#/definitions/point\"","errors":[
+    {"valid":false,"keywordLocation":"/items/$ref/required","absoluteKeywordLocation":"https://example.com/polygon#/definitions/point/required","instanceLocation":"/1","error":"missing required property \"y\""},
+    {"valid":false,"keywordLocation":"/items/$ref/additionalProperties","absoluteKeywordLocation":"https://example.com/polygon#/definitions/point/additionalProperties","instanceLocation":"/1/z","error":"no value is allowed by the false schema"}
+  ]},
+  {"valid":false,"keywordLocation":"/minItems","absoluteKeywordLocation":"https://example.com/polygon#/minItems","instanceLocation":"","error":"array length 2 is less than the minItems 3"}
+]}`,
+	}
+	for format, want := range tests {
+		got, err := verr.MarshalOutput(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := testutil.CanonicalJSON(got), testutil.CanonicalJSON([]byte(want)); string(got) != string(want) {
+			t.Errorf("format %d: got %s, want %s", format, got, want)
+		}
+	}
+
+	t.Run("invalid format", func(t *testing.T) {
+		if _, err := verr.MarshalOutput(OutputFormat(3)); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		var verr *ValidationError
+		got, err := verr.MarshalOutput(BasicOutput)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"valid":true}`; string(got) != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+}
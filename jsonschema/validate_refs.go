@@ -0,0 +1,169 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// schemaIndex locates the (sub)schemas of a root schema by URI, for resolving $refs.
+type schemaIndex struct {
+	byURI          map[string]*Schema   // key is from uriKey
+	dynamicAnchors map[string]*Schema   // schemas with a "$dynamicAnchor" (key is from uriKey)
+	baseOf         map[*Schema]*url.URL // the base URI in effect for each (sub)schema
+	locationOf     map[*Schema]ID       // the canonical location of each (sub)schema
+}
+
+// indexSchema records the URIs that identify each (sub)schema of root.
+//
+// A (sub)schema is identified by its "$id" (if any) and its "$anchor" and "$dynamicAnchor" (if
+// any). Other subschemas are identified by a JSON Pointer fragment relative to an enclosing schema
+// with an "$id" (or the root schema), which is evaluated when resolving a reference.
+func indexSchema(root *Schema) (*schemaIndex, error) {
+	base, err := url.Parse(root.RetrievalURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse retrieval URI: %w", err)
+	}
+	v := &indexVisitor{
+		index: &schemaIndex{
+			byURI:          map[string]*Schema{},
+			dynamicAnchors: map[string]*Schema{},
+			baseOf:         map[*Schema]*url.URL{},
+			locationOf:     map[*Schema]ID{},
+		},
+		dialect:   root.Dialect(),
+		resources: []indexResource{{base: base}},
+		err:       &err,
+	}
+	Walk(v, root)
+	return v.index, err
+}
+
+// resolve returns the schema that schema's $ref refers to.
+func (x *schemaIndex) resolve(schema *Schema) (*Schema, error) {
+	target, _, err := x.resolveReference(schema, "$ref", *schema.Reference)
+	return target, err
+}
+
+// resolveReference returns the schema that the reference (the value of schema's keyword, such as
+// "$ref" or "$dynamicRef") refers to, and the dereferenced URI of the reference.
+func (x *schemaIndex) resolveReference(schema *Schema, keyword, reference string) (*Schema, *url.URL, error) {
+	ref, err := url.Parse(reference)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", keyword, err)
+	}
+	if base := x.baseOf[schema]; base != nil {
+		ref = base.ResolveReference(ref)
+	}
+	if strings.HasPrefix(ref.Fragment, "/") {
+		// Evaluate the JSON Pointer fragment against the schema that the rest of the URI identifies.
+		ptr, err := ParsePointer(ref.Fragment)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", keyword, err)
+		}
+		doc := *ref
+		doc.Fragment, doc.RawFragment = "", ""
+		if resource, ok := x.byURI[uriKey(&doc)]; ok {
+			if target, err := ptr.EvaluateSchema(resource); err == nil {
+				return target, ref, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("failed to resolve %s: %q (dereferenced to %q)", keyword, reference, ref)
+	}
+	target, ok := x.byURI[uriKey(ref)]
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to resolve %s: %q (dereferenced to %q)", keyword, reference, ref)
+	}
+	return target, ref, nil
+}
+
+// uriKey returns the key for a URI in schemaIndex.byURI. The fragment is the decoded fragment, so
+// that (e.g.) "#/definitions/a%20b" and a definition named "a b" have the same key.
+func uriKey(u *url.URL) string {
+	tmp := *u
+	tmp.Fragment = ""
+	tmp.RawFragment = ""
+	return tmp.String() + "#" + u.Fragment
+}
+
+// indexResource is a schema with an "$id" (or the root schema) and the reference tokens from it to
+// the schema currently being visited.
+type indexResource struct {
+	base *url.URL
+	rel  []ReferenceToken
+}
+
+// indexVisitor implements Visitor.
+type indexVisitor struct {
+	index     *schemaIndex
+	dialect   Dialect
+	resources []indexResource
+	err       *error
+}
+
+// Visit implements Visitor.
+func (v *indexVisitor) Visit(schema *Schema, rel []ReferenceToken) Visitor {
+	if schema == nil || *v.err != nil {
+		return nil
+	}
+
+	w := &indexVisitor{index: v.index, dialect: v.dialect, err: v.err}
+	w.resources = make([]indexResource, len(v.resources))
+	for i, r := range v.resources {
+		w.resources[i] = indexResource{base: r.base, rel: appendReferenceTokens(r.rel, rel)}
+	}
+	base := w.resources[len(w.resources)-1].base
+
+	// In draft-07, the "$id" of a schema with a "$ref" is ignored, because all other properties of
+	// such a schema are ignored (https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.3).
+	if schema.ID != nil && (schema.Reference == nil || !v.dialect.refOverridesSiblings()) {
+		u, err := url.Parse(*schema.ID)
+		if err != nil {
+			*v.err = fmt.Errorf("failed to parse $id: %w", err)
+			return nil
+		}
+		u = base.ResolveReference(u)
+		if u.Fragment != "" && (u.Fragment[0] != '/') {
+			// A plain-name fragment identifies the schema but does not change the base URI.
+			v.index.byURI[uriKey(u)] = schema
+		} else {
+			base = u
+			w.resources = append(w.resources, indexResource{base: u})
+		}
+	}
+
+	for _, anchor := range []*string{schema.Anchor, schema.DynamicAnchor} {
+		if anchor != nil {
+			u := *base
+			u.Fragment, u.RawFragment = *anchor, ""
+			v.index.byURI[uriKey(&u)] = schema
+			if anchor == schema.DynamicAnchor {
+				v.index.dynamicAnchors[uriKey(&u)] = schema
+			}
+		}
+	}
+
+	if r := w.resources[len(w.resources)-1]; len(r.rel) == 0 {
+		u := *r.base
+		u.Fragment, u.RawFragment = "", ""
+		if _, ok := v.index.byURI[uriKey(&u)]; !ok {
+			v.index.byURI[uriKey(&u)] = schema
+		}
+	}
+	v.index.baseOf[schema] = base
+
+	// The canonical location is relative to the nearest schema with an "$id".
+	location := ID{Base: w.resources[len(w.resources)-1].base}
+	if rel := w.resources[len(w.resources)-1].rel; len(rel) > 0 {
+		location.ReferenceTokens = rel
+	}
+	v.index.locationOf[schema] = location
+	return w
+}
+
+func appendReferenceTokens(a, b []ReferenceToken) []ReferenceToken {
+	tmp := make([]ReferenceToken, len(a)+len(b))
+	copy(tmp, a)
+	copy(tmp[len(a):], b)
+	return tmp
+}
@@ -0,0 +1,152 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		schema   string
+		instance string
+		wantErr  string // empty if valid
+	}{
+		"valid": {
+			schema:   `{"type":"object","properties":{"a":{"type":"integer"}}}`,
+			instance: `{"a":1.0}`,
+		},
+		"invalid type": {
+			schema:   `{"type":"object","properties":{"a":{"type":"integer"}}}`,
+			instance: `{"a":"x"}`,
+			wantErr:  "instance is invalid against the schema: /a: expected type integer, got string",
+		},
+		"multiple errors": {
+			schema:   `{"required":["a","b"]}`,
+			instance: `{}`,
+			wantErr:  `instance is invalid against the schema: missing required property "a"; missing required property "b"`,
+		},
+		"$ref": {
+			schema:   `{"definitions":{"d":{"minLength":2}},"items":{"$ref":"#/definitions/d"}}`,
+			instance: `["ab","c"]`,
+			wantErr:  "instance is invalid against the schema: /1: string length 1 is less than the minLength 2",
+		},
+		"recursive $ref": {
+			schema:   `{"type":"object","properties":{"child":{"$ref":"#"},"name":{"type":"string"}}}`,
+			instance: `{"child":{"child":{"name":1}}}`,
+			wantErr:  "instance is invalid against the schema: /child/child/name: expected type string, got number",
+		},
+		"$ref with siblings (2020-12)": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","$defs":{"d":{"minLength":2}},"$ref":"#/$defs/d","maxLength":2}`,
+			instance: `"abc"`,
+			wantErr:  "instance is invalid against the schema: string length 3 is greater than the maxLength 2",
+		},
+		"$ref with siblings (draft-07)": {
+			schema:   `{"definitions":{"d":{"minLength":2}},"$ref":"#/definitions/d","maxLength":2}`,
+			instance: `"abc"`,
+		},
+		"$anchor": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","$defs":{"d":{"$anchor":"positive","exclusiveMinimum":0}},"items":{"$ref":"#positive"}}`,
+			instance: `[1,0]`,
+			wantErr:  "instance is invalid against the schema: /1: 0 is not greater than the exclusive minimum 0",
+		},
+		"prefixItems": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","prefixItems":[{"type":"string"}],"items":{"type":"integer"}}`,
+			instance: `["a",1,"b"]`,
+			wantErr:  "instance is invalid against the schema: /2: expected type integer, got string",
+		},
+		"minContains and maxContains": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","contains":{"const":1},"minContains":2,"maxContains":3}`,
+			instance: `[1,2]`,
+			wantErr:  `instance is invalid against the schema: 1 array items are valid against the "contains" schema, fewer than the minContains 2`,
+		},
+		"dependentRequired": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","dependentRequired":{"a":["b"]}}`,
+			instance: `{"a":1}`,
+			wantErr:  `instance is invalid against the schema: property "b" is required by property "a"`,
+		},
+		"dependentSchemas": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","dependentSchemas":{"a":{"required":["b"]}}}`,
+			instance: `{"a":1}`,
+			wantErr:  `instance is invalid against the schema: missing required property "b"`,
+		},
+		"unevaluatedProperties": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","allOf":[{"properties":{"a":true}}],"anyOf":[{"properties":{"b":true},"required":["b"]},{"properties":{"c":true},"required":["c"]}],"unevaluatedProperties":false}`,
+			instance: `{"a":1,"b":2,"c":3,"d":4}`,
+			wantErr:  "instance is invalid against the schema: /d: no value is allowed by the false schema",
+		},
+		"unevaluatedItems": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","prefixItems":[true],"contains":{"type":"string"},"unevaluatedItems":false}`,
+			instance: `[1,"a",2]`,
+			wantErr:  "instance is invalid against the schema: /2: no value is allowed by the false schema",
+		},
+		"$dynamicRef": {
+			schema: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "https://example.com/strict-tree",
+				"$dynamicAnchor": "node",
+				"$ref": "tree",
+				"unevaluatedProperties": false,
+				"$defs": {
+					"tree": {
+						"$id": "tree",
+						"$dynamicAnchor": "node",
+						"type": "object",
+						"properties": {"children": {"type": "array", "items": {"$dynamicRef": "#node"}}}
+					}
+				}
+			}`,
+			instance: `{"children":[{"daat":1}]}`,
+			wantErr:  "instance is invalid against the schema: /children/0/daat: no value is allowed by the false schema; /children: no value is allowed by the false schema",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			err := Validate(&schema, []byte(test.instance))
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("got error %v, want *ValidationError", err)
+			}
+			if err.Error() != test.wantErr {
+				t.Errorf("got error %q, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_nonValidationErrors(t *testing.T) {
+	tests := map[string]struct {
+		schema   string
+		instance string
+	}{
+		"malformed instance": {schema: `{}`, instance: `{`},
+		"trailing data":      {schema: `{}`, instance: `1 2`},
+		"unresolvable $ref":  {schema: `{"$ref":"#/definitions/x"}`, instance: `1`},
+		"invalid regexp":     {schema: `{"pattern":"("}`, instance: `"a"`},
+		"$ref cycle":         {schema: `{"definitions":{"a":{"$ref":"#/definitions/b"},"b":{"$ref":"#/definitions/a"}},"$ref":"#/definitions/a"}`, instance: `1`},
+		"$ref to root":       {schema: `{"$ref":"#"}`, instance: `{"a":[1]}`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			err := Validate(&schema, []byte(test.instance))
+			var verr *ValidationError
+			if err == nil || errors.As(err, &verr) {
+				t.Errorf("got error %v, want non-validation error", err)
+			}
+		})
+	}
+}
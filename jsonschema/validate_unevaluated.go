@@ -0,0 +1,149 @@
+package jsonschema
+
+import "fmt"
+
+// evaluation records the object properties and array items of an instance that were successfully
+// evaluated by a schema (and the subschemas that apply to the same instance), for the
+// "unevaluatedProperties" and "unevaluatedItems" keywords.
+type evaluation struct {
+	properties map[string]bool
+	items      map[int]bool
+}
+
+// validateUnevaluated validates the properties or items of instance (at instLoc) that were not
+// evaluated by the other keywords of schema (at kwLoc) against the "unevaluatedProperties" or
+// "unevaluatedItems" schema.
+func (v *validator) validateUnevaluated(schema *Schema, instance any, instLoc, kwLoc []ReferenceToken) (errs []*ValidationError) {
+	e := evaluation{properties: map[string]bool{}, items: map[int]bool{}}
+	v.evaluate(schema, instance, &e, true)
+
+	sub := func(s *Schema, value any, instRel, kwRel []ReferenceToken, format string, args ...any) {
+		if err := v.validateSubschema(s, value, appendReferenceTokens(instLoc, instRel), appendReferenceTokens(kwLoc, kwRel), fmt.Sprintf(format, args...)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch instance := instance.(type) {
+	case map[string]any:
+		if schema.UnevaluatedProperties != nil {
+			for _, name := range sortedKeys(instance) {
+				if !e.properties[name] {
+					sub(schema.UnevaluatedProperties, instance[name], []ReferenceToken{{Name: name}}, []ReferenceToken{{Name: "unevaluatedProperties", Keyword: true}}, "unevaluated property %q is invalid", name)
+				}
+			}
+		}
+	case []any:
+		if schema.UnevaluatedItems != nil {
+			for i, item := range instance {
+				if !e.items[i] {
+					sub(schema.UnevaluatedItems, item, []ReferenceToken{{Index: i}}, []ReferenceToken{{Name: "unevaluatedItems", Keyword: true}}, "unevaluated array item %d is invalid", i)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// evaluate records in e the properties or items of instance that are evaluated by schema and by
+// its subschemas that apply to the same instance (such as those in "allOf" and "$ref"). Only
+// subschemas that the instance is valid against are considered.
+//
+// If self is true, schema's own "unevaluatedProperties" and "unevaluatedItems" keywords are
+// ignored.
+func (v *validator) evaluate(schema *Schema, instance any, e *evaluation, self bool) {
+	if v.err != nil || schema.IsEmpty || schema.IsNegated {
+		return
+	}
+
+	valid := func(s *Schema) bool { return v.validateSubschema(s, instance, nil, nil, "") == nil }
+	apply := func(s *Schema) {
+		if valid(s) {
+			v.evaluate(s, instance, e, false)
+		}
+	}
+
+	if schema.Reference != nil {
+		target, err := v.refs.resolve(schema)
+		if err != nil {
+			v.err = err
+			return
+		}
+		apply(target)
+		if v.dialect.refOverridesSiblings() {
+			return
+		}
+	}
+	if schema.DynamicRef != nil {
+		target, err := v.resolveDynamicRef(schema)
+		if err != nil {
+			v.err = err
+			return
+		}
+		apply(target)
+	}
+
+	switch instance := instance.(type) {
+	case map[string]any:
+		for name := range instance {
+			if schema.Properties != nil {
+				if _, ok := (*schema.Properties)[name]; ok {
+					e.properties[name] = true
+				}
+			}
+			if schema.PatternProperties != nil {
+				for pattern := range *schema.PatternProperties {
+					if re := v.regexp(pattern); re != nil && re.MatchString(name) {
+						e.properties[name] = true
+					}
+				}
+			}
+			if schema.AdditionalProperties != nil || (!self && schema.UnevaluatedProperties != nil) {
+				e.properties[name] = true
+			}
+		}
+		if schema.DependentSchemas != nil {
+			for name, s := range *schema.DependentSchemas {
+				if _, ok := instance[name]; ok {
+					apply(s)
+				}
+			}
+		}
+
+	case []any:
+		for i, item := range instance {
+			switch {
+			case i < len(schema.PrefixItems):
+				e.items[i] = true
+			case schema.Items != nil && schema.Items.Schema != nil:
+				e.items[i] = true
+			case schema.Items != nil && i < len(schema.Items.Schemas):
+				e.items[i] = true
+			case schema.Items != nil && schema.AdditionalItems != nil:
+				e.items[i] = true
+			case !self && schema.UnevaluatedItems != nil:
+				e.items[i] = true
+			case v.dialect == Draft202012 && schema.Contains != nil && v.validateSubschema(schema.Contains, item, nil, nil, "") == nil:
+				e.items[i] = true
+			}
+		}
+	}
+
+	if schema.If != nil {
+		if valid(schema.If) {
+			v.evaluate(schema.If, instance, e, false)
+			if schema.Then != nil {
+				apply(schema.Then)
+			}
+		} else if schema.Else != nil {
+			apply(schema.Else)
+		}
+	}
+	for _, s := range schema.AllOf {
+		apply(s)
+	}
+	for _, s := range schema.AnyOf {
+		apply(s)
+	}
+	for _, s := range schema.OneOf {
+		apply(s)
+	}
+}
@@ -26,6 +26,11 @@ func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
 
 	// Walk children. The order of the fields matches the their order in the Schema struct type
 	// definition.
+	if schema.Defs != nil {
+		for name, s := range *schema.Defs {
+			walk(v, s, []ReferenceToken{{Name: "$defs", Keyword: true}, {Name: name}})
+		}
+	}
 	if schema.AdditionalItems != nil {
 		walk(v, schema.AdditionalItems, []ReferenceToken{{Name: "additionalItems"}})
 	}
@@ -53,6 +58,11 @@ func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
 			}
 		}
 	}
+	if schema.DependentSchemas != nil {
+		for name, s := range *schema.DependentSchemas {
+			walk(v, s, []ReferenceToken{{Name: "dependentSchemas", Keyword: true}, {Name: name}})
+		}
+	}
 	if schema.Else != nil {
 		walk(v, schema.Else, []ReferenceToken{{Name: "else", Keyword: true}})
 	}
@@ -75,6 +85,9 @@ func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
 			walk(v, s, []ReferenceToken{{Name: "patternProperties", Keyword: true}, {Name: name}})
 		}
 	}
+	for i, s := range schema.PrefixItems {
+		walk(v, s, []ReferenceToken{{Name: "prefixItems", Keyword: true}, {Index: i}})
+	}
 	if schema.Properties != nil {
 		for name, s := range *schema.Properties {
 			walk(v, s, []ReferenceToken{{Name: "properties", Keyword: true}, {Name: name}})
@@ -86,6 +99,12 @@ func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
 	if schema.Then != nil {
 		walk(v, schema.Then, []ReferenceToken{{Name: "then", Keyword: true}})
 	}
+	if schema.UnevaluatedItems != nil {
+		walk(v, schema.UnevaluatedItems, []ReferenceToken{{Name: "unevaluatedItems", Keyword: true}})
+	}
+	if schema.UnevaluatedProperties != nil {
+		walk(v, schema.UnevaluatedProperties, []ReferenceToken{{Name: "unevaluatedProperties", Keyword: true}})
+	}
 
 	v.Visit(nil, rel)
 }